@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestImportAnsibleCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web1", Host: "10.0.0.9", User: "deploy", Tags: []string{"web"}},
+	})
+	path := filepath.Join(t.TempDir(), "hosts.ini")
+	inventory := "[web]\nweb[1:2] ansible_user=deploy\n\n[prod:children]\nweb\n"
+	if err := os.WriteFile(path, []byte(inventory), 0644); err != nil {
+		t.Fatalf("Failed to write inventory: %v", err)
+	}
+
+	output, err := executeCommand(t, "import", "ansible", path, "--on-conflict", "overwrite", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"~ web1  deploy@web1",
+		`host: "10.0.0.9" -> "web1"`,
+		`tags: ["web"] -> ["web","prod"]`,
+		"+ web2  deploy@web2",
+		"1 to add, 1 to update",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	local, _ := config.LoadLocalConfig()
+	if len(local.Servers) != 1 || local.Servers["web1"].Host != "web1" {
+		t.Errorf("Expected web1 to be overridden locally, got %v", local.Servers)
+	}
+	if len(local.Private) != 1 || local.Private[0].Name != "web2" {
+		t.Errorf("Expected web2 to be added, got %v", local.Private)
+	}
+}
+
+func TestExportAnsibleCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"web", "prod"}},
+		{Name: "db-1", Host: "10.0.1.1", Tags: []string{"db"}},
+	})
+
+	output, err := executeCommand(t, "export", "ansible", "--tags", "prod")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "[web]\nweb-1 ansible_host=10.0.0.1 ansible_user=deploy\n\n[prod]\nweb-1\n"
+	if output != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, output)
+	}
+
+	output, err = executeCommand(t, "export", "ansible", "--format", "yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "all:\n  children:\n    web:\n      hosts:\n        web-1:\n          ansible_host: 10.0.0.1\n") {
+		t.Errorf("Expected a YAML inventory, got:\n%s", output)
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/spf13/cobra"
+)
+
+type authClass string
+
+const (
+	authSuccess          authClass = "success"
+	authPermissionDenied authClass = "permission denied"
+	authHostKeyMismatch  authClass = "host key mismatch"
+	authHostKeyUnknown   authClass = "unknown host key"
+	authTimeout          authClass = "timeout"
+	authDNSFailure       authClass = "DNS failure"
+	authRefused          authClass = "connection refused"
+	authOther            authClass = "other"
+)
+
+// authClassOrder is the order classes are reported in, failures first.
+var authClassOrder = []authClass{
+	authPermissionDenied,
+	authHostKeyMismatch,
+	authHostKeyUnknown,
+	authTimeout,
+	authDNSFailure,
+	authRefused,
+	authOther,
+	authSuccess,
+}
+
+var authCheckCmd = &cobra.Command{
+	Use:   "auth-check [names...]",
+	Short: "Check that key-based login works on servers",
+	Long: `Log in to each selected server non-interactively (BatchMode, no TTY) and run
+"true". Each result is classified as success, permission denied, host key
+mismatch, unknown host key, timeout, DNS failure, connection refused or other,
+and the report is grouped by class.
+
+Without names, --tags or --all, every server is checked. The command exits
+non-zero if any login failed, so it can be run after key rotations or when
+onboarding someone.`,
+	Example: `  sshy auth-check
+  sshy auth-check --tags prod
+  sshy auth-check web-1 web-2 --timeout 10s`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		tags, all := selectionFromFlags(cmd)
+
+		inventory, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 && len(tags) == 0 {
+			all = true
+		}
+		selected, err := selectServers(inventory, args, tags, all)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		opts := execOptions{Concurrency: concurrency, Timeout: timeout, Capture: true}
+		results := runOnServers(ctx, inventory, selected, "true", opts, io.Discard, io.Discard)
+
+		logins := make([]string, len(selected))
+		for i, s := range selected {
+			logins[i] = sshcmd.UserHost(s, s.User)
+		}
+		failed := printAuthReport(cmd.OutOrStdout(), results, logins)
+		if failed > 0 {
+			return fmt.Errorf("login failed on %d of %d servers", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// classifyAuth decides why a login attempt failed from ssh's exit status and
+// stderr. ssh exits with 255 for its own errors; any other status means the
+// login worked and "true" itself failed.
+func classifyAuth(r hostResult) (authClass, string) {
+	detail := lastLine(r.Stderr)
+	if detail == "" {
+		detail = r.Error
+	}
+	stderr := strings.ToLower(r.Stderr)
+
+	switch {
+	case !r.failed():
+		return authSuccess, ""
+	case r.Error == "timed out":
+		return authTimeout, "timed out"
+	case strings.Contains(stderr, "remote host identification has changed"),
+		strings.Contains(stderr, "host key for") && strings.Contains(stderr, "has changed"):
+		return authHostKeyMismatch, detail
+	case strings.Contains(stderr, "host key verification failed"):
+		// With BatchMode, ssh cannot ask to accept a host it does not know.
+		return authHostKeyUnknown, detail
+	case strings.Contains(stderr, "permission denied"),
+		strings.Contains(stderr, "too many authentication failures"):
+		return authPermissionDenied, detail
+	case strings.Contains(stderr, "could not resolve hostname"),
+		strings.Contains(stderr, "name or service not known"),
+		strings.Contains(stderr, "nodename nor servname"),
+		strings.Contains(stderr, "temporary failure in name resolution"):
+		return authDNSFailure, detail
+	case strings.Contains(stderr, "timed out"):
+		return authTimeout, detail
+	case strings.Contains(stderr, "connection refused"):
+		return authRefused, detail
+	case r.ExitCode > 0 && r.ExitCode != 255:
+		return authOther, fmt.Sprintf("logged in, but the command exited with %d", r.ExitCode)
+	}
+	return authOther, detail
+}
+
+// printAuthReport prints results grouped by class and returns the number of
+// failed logins.
+func printAuthReport(w io.Writer, results []hostResult, logins []string) int {
+	type entry struct {
+		result hostResult
+		login  string
+		detail string
+	}
+	groups := make(map[authClass][]entry)
+	for i, r := range results {
+		class, detail := classifyAuth(r)
+		groups[class] = append(groups[class], entry{result: r, login: logins[i], detail: detail})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	first := true
+	for _, class := range authClassOrder {
+		entries := groups[class]
+		if len(entries) == 0 {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(tw)
+		}
+		first = false
+		fmt.Fprintf(tw, "%s (%d)\n", class, len(entries))
+		for _, e := range entries {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", e.result.Name, e.login, e.result.Duration.Round(time.Millisecond), e.detail)
+		}
+	}
+	tw.Flush()
+
+	failed := len(results) - len(groups[authSuccess])
+	fmt.Fprintf(w, "\n%d succeeded, %d failed\n", len(results)-failed, failed)
+	return failed
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func init() {
+	rootCmd.AddCommand(authCheckCmd)
+
+	addSelectionFlags(authCheckCmd)
+	authCheckCmd.Flags().IntP("concurrency", "c", 20, "Maximum number of servers to check at once")
+	authCheckCmd.Flags().Duration("timeout", 15*time.Second, "Per-server timeout")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestClassifyAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   hostResult
+		expected authClass
+		detail   string
+	}{
+		{"success", hostResult{}, authSuccess, ""},
+		{
+			"permission denied",
+			hostResult{ExitCode: 255, Stderr: "deploy@10.0.0.1: Permission denied (publickey).\n"},
+			authPermissionDenied, "deploy@10.0.0.1: Permission denied (publickey).",
+		},
+		{
+			"host key changed",
+			hostResult{ExitCode: 255, Stderr: "@@@@@@@\n@    WARNING: REMOTE HOST IDENTIFICATION HAS CHANGED!     @\n@@@@@@@\nHost key verification failed.\n"},
+			authHostKeyMismatch, "Host key verification failed.",
+		},
+		{
+			"host key unknown",
+			hostResult{ExitCode: 255, Stderr: "No ED25519 host key is known for web-9.internal and you have requested strict checking.\nHost key verification failed.\n"},
+			authHostKeyUnknown, "Host key verification failed.",
+		},
+		{"timeout", hostResult{ExitCode: -1, Error: "timed out"}, authTimeout, "timed out"},
+		{
+			"connect timeout",
+			hostResult{ExitCode: 255, Stderr: "ssh: connect to host 10.0.0.9 port 22: Connection timed out\n"},
+			authTimeout, "ssh: connect to host 10.0.0.9 port 22: Connection timed out",
+		},
+		{
+			"dns",
+			hostResult{ExitCode: 255, Stderr: "ssh: Could not resolve hostname web-9.internal: Name or service not known\n"},
+			authDNSFailure, "ssh: Could not resolve hostname web-9.internal: Name or service not known",
+		},
+		{
+			"refused",
+			hostResult{ExitCode: 255, Stderr: "ssh: connect to host 10.0.0.1 port 22: Connection refused\n"},
+			authRefused, "ssh: connect to host 10.0.0.1 port 22: Connection refused",
+		},
+		{"command failed", hostResult{ExitCode: 1}, authOther, "logged in, but the command exited with 1"},
+		{"ssh missing", hostResult{ExitCode: -1, Error: "exec: \"ssh\": executable file not found in $PATH"}, authOther, "exec: \"ssh\": executable file not found in $PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, detail := classifyAuth(tt.result)
+			if class != tt.expected {
+				t.Errorf("Expected class %q, got %q", tt.expected, class)
+			}
+			if detail != tt.detail {
+				t.Errorf("Expected detail %q, got %q", tt.detail, detail)
+			}
+		})
+	}
+}
+
+func TestAuthCheckCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy"},
+		{Name: "web-2", Host: "10.0.0.2", User: "deploy"},
+		{Name: "web-3", Host: "10.0.0.3", User: "deploy"},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			switch args[len(args)-2] {
+			case "deploy@10.0.0.2", "deploy@10.0.0.3":
+				fmt.Fprintln(stderr, "deploy@host: Permission denied (publickey).")
+				return mockExitError(255)
+			}
+			return nil
+		},
+	}
+	cmdRunner = mock
+
+	out, err := executeCommand(t, "auth-check")
+	if err == nil || err.Error() != "login failed on 2 of 3 servers" {
+		t.Errorf("Expected login failure error, got %v", err)
+	}
+
+	denied := strings.Index(out, "permission denied (2)")
+	success := strings.Index(out, "success (1)")
+	if denied < 0 || success < 0 || denied > success {
+		t.Errorf("Expected failures grouped before successes, got:\n%s", out)
+	}
+	if !strings.Contains(out, "1 succeeded, 2 failed") {
+		t.Errorf("Expected summary, got:\n%s", out)
+	}
+
+	for _, call := range mock.Calls {
+		if !contains(call, "-T") || !containsSequence(call, "-o", "BatchMode=yes") || call[len(call)-1] != "true" {
+			t.Errorf("Expected a batch mode login running true, got %v", call)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -9,11 +11,16 @@ import (
 	"github.com/ktr0731/go-fuzzyfinder"
 	"github.com/omisai-tech/sshy/internal/config"
 	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
 	"github.com/spf13/cobra"
 )
 
 type CommandRunner interface {
+	// Run executes the command attached to the terminal.
 	Run(name string, args []string) error
+	// RunContext executes the command without stdin, writing its output to
+	// the given writers. The command is killed when ctx is done.
+	RunContext(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error
 }
 
 type DefaultCommandRunner struct{}
@@ -26,16 +33,31 @@ func (r *DefaultCommandRunner) Run(name string, args []string) error {
 	return cmd.Run()
 }
 
+func (r *DefaultCommandRunner) RunContext(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
 var cmdRunner CommandRunner = &DefaultCommandRunner{}
 
-var fuzzyFind = func(names []string, itemFunc func(int) string) (int, error) {
-	return fuzzyfinder.Find(names, itemFunc)
+var fuzzyFind = func(names []string, itemFunc func(int) string, preview func(int) string) (int, error) {
+	if preview == nil {
+		return fuzzyfinder.Find(names, itemFunc)
+	}
+	return fuzzyfinder.Find(names, itemFunc, fuzzyfinder.WithPreviewWindow(func(i, width, height int) string {
+		if i < 0 {
+			return ""
+		}
+		return preview(i)
+	}))
 }
 
 var connectCmd = &cobra.Command{
 	Use:   "connect [name] [ssh-flags...] [command]",
 	Short: "Connect to an SSH server",
-	Long:  "Connect to the specified SSH server or select one interactively if no name is provided. SSH flags can be passed through. Use -- to separate SSH options from remote commands.",
+	Long:  "Connect to the specified SSH server or select one interactively if no name is provided. SSH flags can be passed through. Use -- to separate SSH options from remote commands. Use --dry-run to show the resolved server and command without running it, or --print to only print the command line. In the interactive picker, --check marks unreachable servers and --reachable hides them. Use --refresh-servers to fetch remote server lists even when the cached copy is fresh.",
 	Args:  cobra.MinimumNArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		for _, arg := range args {
@@ -43,13 +65,16 @@ var connectCmd = &cobra.Command{
 				return cmd.Help()
 			}
 		}
+		args, mode := extractPrintFlags(args)
+		args = extractRefreshFlag(args)
+		args, check, reachableOnly := extractPickerFlags(args)
 
 		cfg, err := config.LoadGlobalConfig()
 		if err != nil {
 			return fmt.Errorf("error loading config: %w", err)
 		}
 
-		servers, err := config.LoadServersWithPath(cfg.ConfigPath, cfg.GetServersSource())
+		servers, err := config.LoadServersFromConfig(cfg)
 		if err != nil {
 			return fmt.Errorf("error loading servers: %w", err)
 		}
@@ -59,25 +84,32 @@ var connectCmd = &cobra.Command{
 		var remoteCommand string
 
 		if len(args) == 0 {
+			candidates := servers
 			names := make([]string, len(servers))
 			for i, s := range servers {
 				names[i] = s.Name
 			}
-			idx, err := fuzzyFind(names, func(i int) string { return names[i] })
+			if check || reachableOnly {
+				candidates, names = checkCandidates(servers, reachableOnly)
+				if len(candidates) == 0 {
+					fmt.Println("No reachable servers")
+					return nil
+				}
+			}
+			cachedFacts := loadCachedFactsDetails(candidates)
+			idx, err := fuzzyFind(names, func(i int) string { return names[i] }, func(i int) string {
+				return serverPreview(candidates[i], cachedFacts)
+			})
 			if err != nil {
 				fmt.Println("No server selected")
 				return nil
 			}
-			selectedServer = servers[idx]
+			selectedServer = candidates[idx]
 		} else {
 			name := args[0]
-			for _, s := range servers {
-				if s.Name == name {
-					selectedServer = s
-					break
-				}
-			}
-			if selectedServer.Name == "" {
+			var found bool
+			selectedServer, found = findServer(servers, name)
+			if !found {
 				return fmt.Errorf("server not found: %s", name)
 			}
 
@@ -98,69 +130,81 @@ var connectCmd = &cobra.Command{
 				sshArgs = remainingArgs
 			}
 		}
-		connectTo(selectedServer, sshArgs, remoteCommand)
-		return nil
-	},
-}
-
-func buildSSHArgs(s models.Server, sshArgs []string, remoteCommand string) []string {
-	args := []string{}
-
-	if s.Key != "" {
-		args = append(args, "-i", s.Key)
-	}
-
-	if s.Port != 0 && s.Port != 22 {
-		args = append(args, "-p", fmt.Sprintf("%d", s.Port))
-	}
 
-	for key, value := range s.Options {
-		switch key {
-		case "ForwardAgent":
-			if value == "yes" {
-				args = append(args, "-A")
-			}
-		case "RequestTTY":
-			switch value {
-			case "force":
-				args = append(args, "-t", "-t")
-			case "yes":
-				args = append(args, "-t")
+		target, err := sshcmd.NewTarget(selectedServer, servers)
+		if err != nil {
+			return err
+		}
+		if mode != printNone {
+			inv, err := sshcmd.SSH(target, sshArgs, remoteCommand)
+			if err != nil {
+				return err
 			}
-		case "LocalForward":
-			args = append(args, "-L", fmt.Sprintf("%v", value))
+			return printInvocation(cmd.OutOrStdout(), mode, &target, inv)
 		}
-	}
-
-	args = append(args, sshArgs...)
+		return connectTo(target, sshArgs, remoteCommand)
+	},
+}
 
-	hasUserFlag := false
-	for _, arg := range sshArgs {
-		if arg == "-l" {
-			hasUserFlag = true
+// extractPickerFlags removes --check and --reachable from args. --check marks
+// unreachable servers in the picker; --reachable hides them.
+func extractPickerFlags(args []string) ([]string, bool, bool) {
+	check, reachableOnly := false, false
+	remaining := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			remaining = append(remaining, args[i:]...)
 			break
 		}
+		switch arg {
+		case "--check":
+			check = true
+		case "--reachable":
+			reachableOnly = true
+		default:
+			remaining = append(remaining, arg)
+		}
 	}
+	return remaining, check, reachableOnly
+}
 
-	userHost := s.Host
-	if s.User != "" && !hasUserFlag {
-		userHost = s.User + "@" + s.Host
+// checkCandidates probes servers and returns the picker entries with their
+// labels, either marking or dropping the unreachable ones.
+func checkCandidates(servers models.Servers, reachableOnly bool) (models.Servers, []string) {
+	var candidates models.Servers
+	var names []string
+	for i, r := range checkServers(context.Background(), servers, servers, 20, defaultCheckTimeout) {
+		switch {
+		case r.Up():
+			candidates = append(candidates, servers[i])
+			names = append(names, servers[i].Name)
+		case !reachableOnly:
+			candidates = append(candidates, servers[i])
+			names = append(names, fmt.Sprintf("%s (unreachable: %v)", servers[i].Name, r.Err))
+		}
 	}
-	args = append(args, userHost)
+	return candidates, names
+}
 
-	if remoteCommand != "" {
-		args = append(args, remoteCommand)
+func findServer(servers models.Servers, name string) (models.Server, bool) {
+	for _, s := range servers {
+		if s.Name == name {
+			return s, true
+		}
 	}
-
-	return args
+	return models.Server{}, false
 }
 
-func connectTo(s models.Server, sshArgs []string, remoteCommand string) {
-	args := buildSSHArgs(s, sshArgs, remoteCommand)
-	err := cmdRunner.Run("ssh", args)
+func connectTo(t sshcmd.Target, sshArgs []string, remoteCommand string) error {
+	inv, err := sshcmd.SSH(t, sshArgs, remoteCommand)
+	if err != nil {
+		return err
+	}
+	err = cmdRunner.Run(inv.Program, inv.Args)
 	if err != nil {
 		fmt.Println("Error connecting:", err)
 	}
+	return nil
 }
 
 func init() {
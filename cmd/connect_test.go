@@ -1,27 +1,78 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"gopkg.in/yaml.v3"
 )
 
 type MockCommandRunner struct {
+	mu          sync.Mutex
 	LastCommand string
 	LastArgs    []string
+	Calls       [][]string
 	ShouldError bool
+	RunFunc     func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error
 }
 
 func (m *MockCommandRunner) Run(name string, args []string) error {
+	return m.RunContext(context.Background(), name, args, io.Discard, io.Discard)
+}
+
+func (m *MockCommandRunner) RunContext(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	m.mu.Lock()
 	m.LastCommand = name
 	m.LastArgs = args
+	m.Calls = append(m.Calls, append([]string{name}, args...))
+	m.mu.Unlock()
+	if m.RunFunc != nil {
+		return m.RunFunc(ctx, name, args, stdout, stderr)
+	}
 	if m.ShouldError {
 		return errors.New("mock error")
 	}
 	return nil
 }
 
+type mockExitError int
+
+func (e mockExitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func (e mockExitError) ExitCode() int {
+	return int(e)
+}
+
+func setupTestServers(t *testing.T, servers models.Servers) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	sshyDir := filepath.Join(home, ".sshy")
+	if err := os.MkdirAll(sshyDir, 0755); err != nil {
+		t.Fatalf("Failed to create .sshy dir: %v", err)
+	}
+	cfg := fmt.Sprintf("servers_path: servers.yaml\nconfig_path: %s\n", sshyDir)
+	if err := os.WriteFile(filepath.Join(sshyDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	data, _ := yaml.Marshal(servers)
+	if err := os.WriteFile(filepath.Join(sshyDir, "servers.yaml"), data, 0644); err != nil {
+		t.Fatalf("Failed to write servers: %v", err)
+	}
+}
+
 func TestConnectTo(t *testing.T) {
 	oldRunner := cmdRunner
 	defer func() { cmdRunner = oldRunner }()
@@ -36,7 +87,7 @@ func TestConnectTo(t *testing.T) {
 		Key:  "~/.ssh/id_rsa",
 	}
 
-	connectTo(server, []string{"-v"}, "ls -la")
+	connectTo(sshcmd.Target{Server: server}, []string{"-v"}, "ls -la")
 
 	if mock.LastCommand != "ssh" {
 		t.Errorf("Expected command 'ssh', got '%s'", mock.LastCommand)
@@ -72,7 +123,7 @@ func TestConnectTo_Error(t *testing.T) {
 		User: "admin",
 	}
 
-	connectTo(server, []string{}, "")
+	connectTo(sshcmd.Target{Server: server}, []string{}, "")
 }
 
 func TestDefaultCommandRunner(t *testing.T) {
@@ -83,239 +134,24 @@ func TestDefaultCommandRunner(t *testing.T) {
 	}
 }
 
-func TestBuildSSHArgs(t *testing.T) {
-	tests := []struct {
-		name          string
-		server        models.Server
-		sshArgs       []string
-		remoteCommand string
-		checkFunc     func(t *testing.T, args []string)
-	}{
-		{
-			name: "basic server with key",
-			server: models.Server{
-				Name: "test",
-				Host: "example.com",
-				User: "admin",
-				Key:  "~/.ssh/id_rsa",
-				Port: 22,
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				hasKey := containsSequence(args, "-i", "~/.ssh/id_rsa")
-				if !hasKey {
-					t.Error("Expected -i flag with key")
-				}
-				if !contains(args, "admin@example.com") {
-					t.Error("Expected user@host in args")
-				}
-			},
-		},
-		{
-			name: "server with custom port",
-			server: models.Server{
-				Name: "test",
-				Host: "example.com",
-				User: "admin",
-				Port: 2222,
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				hasPort := containsSequence(args, "-p", "2222")
-				if !hasPort {
-					t.Error("Expected -p flag with port 2222")
-				}
-			},
-		},
-		{
-			name: "server without user",
-			server: models.Server{
-				Name: "test",
-				Host: "example.com",
-				Port: 22,
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				if !contains(args, "example.com") {
-					t.Error("Expected host without user prefix")
-				}
-				if contains(args, "@") {
-					for _, arg := range args {
-						if arg == "example.com" {
-							return
-						}
-					}
-					t.Error("Should not have @ in host when user is empty")
-				}
-			},
-		},
-		{
-			name: "server with user override via -l flag",
-			server: models.Server{
-				Name: "test",
-				Host: "example.com",
-				User: "admin",
-				Port: 22,
-			},
-			sshArgs:       []string{"-l", "root"},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				if contains(args, "admin@example.com") {
-					t.Error("Should not prepend user when -l flag is used")
-				}
-				if !contains(args, "example.com") {
-					t.Error("Expected host in args")
-				}
-			},
-		},
-		{
-			name: "server with remote command",
-			server: models.Server{
-				Name: "test",
-				Host: "example.com",
-				User: "admin",
-			},
-			sshArgs:       []string{},
-			remoteCommand: "ls -la",
-			checkFunc: func(t *testing.T, args []string) {
-				if !contains(args, "ls -la") {
-					t.Error("Expected remote command in args")
-				}
-			},
-		},
-		{
-			name: "server with ForwardAgent option",
-			server: models.Server{
-				Name:    "test",
-				Host:    "example.com",
-				User:    "admin",
-				Options: map[string]interface{}{"ForwardAgent": "yes"},
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				if !contains(args, "-A") {
-					t.Error("Expected -A flag for ForwardAgent")
-				}
-			},
-		},
-		{
-			name: "server with ForwardAgent no",
-			server: models.Server{
-				Name:    "test",
-				Host:    "example.com",
-				User:    "admin",
-				Options: map[string]interface{}{"ForwardAgent": "no"},
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				if contains(args, "-A") {
-					t.Error("Should not have -A flag when ForwardAgent is no")
-				}
-			},
-		},
-		{
-			name: "server with RequestTTY force",
-			server: models.Server{
-				Name:    "test",
-				Host:    "example.com",
-				User:    "admin",
-				Options: map[string]interface{}{"RequestTTY": "force"},
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				count := 0
-				for _, arg := range args {
-					if arg == "-t" {
-						count++
-					}
-				}
-				if count != 2 {
-					t.Errorf("Expected two -t flags for RequestTTY force, got %d", count)
-				}
-			},
-		},
-		{
-			name: "server with RequestTTY yes",
-			server: models.Server{
-				Name:    "test",
-				Host:    "example.com",
-				User:    "admin",
-				Options: map[string]interface{}{"RequestTTY": "yes"},
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				count := 0
-				for _, arg := range args {
-					if arg == "-t" {
-						count++
-					}
-				}
-				if count != 1 {
-					t.Errorf("Expected one -t flag for RequestTTY yes, got %d", count)
-				}
-			},
-		},
-		{
-			name: "server with LocalForward option",
-			server: models.Server{
-				Name:    "test",
-				Host:    "example.com",
-				User:    "admin",
-				Options: map[string]interface{}{"LocalForward": "8080:localhost:80"},
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				if !containsSequence(args, "-L", "8080:localhost:80") {
-					t.Error("Expected -L flag with LocalForward value")
-				}
-			},
-		},
-		{
-			name: "server with additional ssh args",
-			server: models.Server{
-				Name: "test",
-				Host: "example.com",
-				User: "admin",
-			},
-			sshArgs:       []string{"-v", "-C"},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				if !contains(args, "-v") || !contains(args, "-C") {
-					t.Error("Expected additional ssh args in output")
-				}
-			},
-		},
-		{
-			name: "server with port 0",
-			server: models.Server{
-				Name: "test",
-				Host: "example.com",
-				User: "admin",
-				Port: 0,
-			},
-			sshArgs:       []string{},
-			remoteCommand: "",
-			checkFunc: func(t *testing.T, args []string) {
-				if contains(args, "-p") {
-					t.Error("Should not have -p flag when port is 0")
-				}
-			},
-		},
+func TestConnectCmd_JumpHost(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "bastion", Host: "bastion.example.com", User: "ops", Port: 2222},
+		{Name: "db", Host: "db.internal", User: "admin", Jump: models.JumpChain{"bastion"}},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	if err := connectCmd.RunE(connectCmd, []string{"db", "--", "uptime"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			args := buildSSHArgs(tt.server, tt.sshArgs, tt.remoteCommand)
-			tt.checkFunc(t, args)
-		})
+	expected := []string{"-o", "ProxyCommand=ssh -p 2222 -W %h:%p ops@bastion.example.com", "admin@db.internal", "uptime"}
+	if strings.Join(mock.LastArgs, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, mock.LastArgs)
 	}
 }
 
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestExportCSVCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"web"}},
+	})
+
+	output, err := executeCommand(t, "export", "csv", "--column", "name=Hostname")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "source,Hostname,host,user,port,key,tags,jump\n[S],web-1,10.0.0.1,deploy,,,web,\n"
+	if output != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestImportCSVCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	path := filepath.Join(t.TempDir(), "hosts.csv")
+	content := "Hostname,IP,Groups\nweb-1,10.0.0.1,web;prod\ndb-1,10.0.1.1,db\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+
+	output, err := executeCommand(t, "import", "csv", path, "--column", "name=Hostname", "--column", "host=IP", "--column", "tags=Groups", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "Imported 2 servers") {
+		t.Errorf("Expected 2 servers imported, got:\n%s", output)
+	}
+	local, _ := config.LoadLocalConfig()
+	if len(local.Private) != 2 || strings.Join(local.Private[0].Tags, ",") != "web,prod" {
+		t.Errorf("Expected the servers with their tags, got %+v", local.Private)
+	}
+}
+
+func TestImportCSVCmd_Stdin(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	defer rootCmd.SetIn(nil)
+
+	rootCmd.SetIn(strings.NewReader("name,host\nweb-1,10.0.0.1\n"))
+	_, err := executeCommand(t, "import", "csv", "-")
+	if err == nil || !strings.Contains(err.Error(), "needs --yes") {
+		t.Errorf("Expected stdin without --yes to be refused, got %v", err)
+	}
+
+	rootCmd.SetIn(strings.NewReader("name,host\nweb-1,10.0.0.1\n"))
+	output, err := executeCommand(t, "import", "csv", "-", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "Imported 1 servers") {
+		t.Errorf("Expected 1 server imported, got:\n%s", output)
+	}
+}
+
+func TestImportCSVCmd_Cancelled(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	path := filepath.Join(t.TempDir(), "hosts.csv")
+	if err := os.WriteFile(path, []byte("name,host\nweb-1,10.0.0.1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	defer rootCmd.SetIn(nil)
+	rootCmd.SetIn(strings.NewReader("n\n"))
+
+	_, err := executeCommand(t, "import", "csv", path)
+	if err == nil || !strings.Contains(err.Error(), "import cancelled") {
+		t.Errorf("Expected a cancelled import to fail, got %v", err)
+	}
+	local, _ := config.LoadLocalConfig()
+	if len(local.Private) != 0 {
+		t.Errorf("Expected nothing imported, got %+v", local.Private)
+	}
+}
+
+func TestImportCSVCmd_InvalidRows(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	path := filepath.Join(t.TempDir(), "hosts.csv")
+	if err := os.WriteFile(path, []byte("name,host,port\nweb-1,10.0.0.1,22\nweb-2,,\n"), 0644); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+
+	_, err := executeCommand(t, "import", "csv", path, "--yes")
+	if err == nil || !strings.Contains(err.Error(), "row 3: web-2: missing host") {
+		t.Errorf("Expected a row error, got %v", err)
+	}
+	local, _ := config.LoadLocalConfig()
+	if len(local.Private) != 0 {
+		t.Errorf("Expected nothing to be imported, got %v", local.Private)
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ktr0731/go-fuzzyfinder"
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/discover"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var fuzzyFindMulti = func(items []string, itemFunc func(int) string) ([]int, error) {
+	return fuzzyfinder.FindMulti(items, itemFunc, fuzzyfinder.WithHeader("Tab to select, Enter to add"))
+}
+
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Suggest servers from known_hosts and shell history",
+	Long: `Find hosts you connect to that are not in the inventory yet.
+
+~/.ssh/known_hosts and the bash, zsh and fish history files are scanned for
+ssh invocations, and the hosts missing from the merged inventory are listed,
+most used first. Hashed known_hosts entries are only recognised for hosts
+that also appear in history. The user and port given on the ssh command line
+are carried over.
+
+Pick the servers to add in the multi-select (Tab to select, Enter to add);
+they are added to the private servers in your local config, named after
+their host. --list only prints the suggestions.`,
+	Example: `  sshy discover
+  sshy discover --list
+  sshy discover --tag discovered`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, _ := cmd.Flags().GetBool("list")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		out := cmd.OutOrStdout()
+
+		cfg, err := config.LoadGlobalConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+		existing, err := config.LoadServersWithSourceFromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("error loading servers: %w", err)
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		result, err := discover.Scan(discover.DefaultSources(home))
+		if err != nil {
+			return fmt.Errorf("error scanning history: %w", err)
+		}
+
+		inventory := make(models.Servers, len(existing))
+		for i, s := range existing {
+			inventory[i] = s.Server
+		}
+		candidates := discover.NotIn(result.Candidates, inventory)
+		if result.Unresolved > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %d hashed known_hosts entries not seen in history\n", result.Unresolved)
+		}
+		if len(candidates) == 0 {
+			fmt.Fprintln(out, "No new servers found")
+			return nil
+		}
+		if list {
+			printCandidates(out, candidates)
+			return nil
+		}
+
+		labels := make([]string, len(candidates))
+		for i, c := range candidates {
+			labels[i] = fmt.Sprintf("%s  (%s)", candidateLogin(c), candidateUsage(c))
+		}
+		selected, err := fuzzyFindMulti(labels, func(i int) string { return labels[i] })
+		if err != nil || len(selected) == 0 {
+			fmt.Fprintln(out, "No servers selected")
+			return nil
+		}
+
+		servers := make(models.Servers, len(selected))
+		for i, idx := range selected {
+			servers[i] = candidates[idx].Server()
+			servers[i].Tags = mergeTags(servers[i].Tags, tags)
+		}
+		return addPrivateServers(out, cfg, existing, servers)
+	},
+}
+
+func printCandidates(w io.Writer, candidates []discover.Candidate) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tLOGIN\tUSES\tKNOWN_HOSTS")
+	for _, c := range candidates {
+		known := ""
+		if c.KnownHost {
+			known = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", c.Host, candidateLogin(c), c.Uses, known)
+	}
+	tw.Flush()
+}
+
+// candidateLogin renders a candidate as [user@]host[:port].
+func candidateLogin(c discover.Candidate) string {
+	login := c.Host
+	if c.User != "" {
+		login = c.User + "@" + login
+	}
+	if c.Port != 0 {
+		login = fmt.Sprintf("%s:%d", login, c.Port)
+	}
+	return login
+}
+
+func candidateUsage(c discover.Candidate) string {
+	var parts []string
+	switch c.Uses {
+	case 0:
+	case 1:
+		parts = append(parts, "used once")
+	default:
+		parts = append(parts, fmt.Sprintf("used %d times", c.Uses))
+	}
+	if c.KnownHost {
+		parts = append(parts, "in known_hosts")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(discoverCmd)
+	discoverCmd.Flags().Bool("list", false, "Only list the suggested servers")
+	discoverCmd.Flags().StringSlice("tag", []string{}, "Add these tags to every added server")
+}
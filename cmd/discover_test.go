@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func writeDiscoverHistory(t *testing.T) {
+	t.Helper()
+	home, _ := os.UserHomeDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	history := strings.Join([]string{
+		"ssh web-1",
+		"ssh -p 2222 admin@db.example.com",
+		"ssh admin@db.example.com -p 2222 uptime",
+		"ssh 10.0.0.7",
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(home, ".bash_history"), []byte(history), 0600); err != nil {
+		t.Fatalf("Failed to write history: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatalf("Failed to create .ssh: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "known_hosts"), []byte("10.0.0.7 ssh-ed25519 AAAA\n"), 0600); err != nil {
+		t.Fatalf("Failed to write known_hosts: %v", err)
+	}
+}
+
+func TestDiscoverCmd_List(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "web-1", Host: "web-1.example.com"}})
+	writeDiscoverHistory(t)
+
+	output, err := executeCommand(t, "discover", "--list")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 servers, got:\n%s", output)
+	}
+	if !strings.HasPrefix(lines[1], "db.example.com  admin@db.example.com:2222  2") {
+		t.Errorf("Expected db.example.com first, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "10.0.0.7") || !strings.HasSuffix(lines[2], "yes") {
+		t.Errorf("Expected 10.0.0.7 from known_hosts second, got %q", lines[2])
+	}
+}
+
+func TestDiscoverCmd_Add(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "web-1", Host: "web-1.example.com"}})
+	writeDiscoverHistory(t)
+
+	oldFind := fuzzyFindMulti
+	defer func() { fuzzyFindMulti = oldFind }()
+	var labels []string
+	fuzzyFindMulti = func(items []string, itemFunc func(int) string) ([]int, error) {
+		for i := range items {
+			labels = append(labels, itemFunc(i))
+		}
+		return []int{0}, nil
+	}
+
+	output, err := executeCommand(t, "discover", "--tag", "discovered")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectedLabels := []string{
+		"admin@db.example.com:2222  (used 2 times)",
+		"10.0.0.7  (used once, in known_hosts)",
+	}
+	if fmt.Sprint(labels) != fmt.Sprint(expectedLabels) {
+		t.Errorf("Expected labels %v, got %v", expectedLabels, labels)
+	}
+	if !strings.Contains(output, "+ db.example.com  admin@db.example.com:2222") {
+		t.Errorf("Expected db.example.com to be added, got:\n%s", output)
+	}
+
+	local, err := config.LoadLocalConfig()
+	if err != nil {
+		t.Fatalf("Failed to load local config: %v", err)
+	}
+	if len(local.Private) != 1 {
+		t.Fatalf("Expected 1 private server, got %v", local.Private)
+	}
+	s := local.Private[0]
+	if s.Name != "db.example.com" || s.User != "admin" || s.Port != 2222 || !contains(s.Tags, "discovered") {
+		t.Errorf("Expected db.example.com with user, port and tag, got %+v", s)
+	}
+}
+
+func TestDiscoverCmd_NoneSelected(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	writeDiscoverHistory(t)
+
+	oldFind := fuzzyFindMulti
+	defer func() { fuzzyFindMulti = oldFind }()
+	fuzzyFindMulti = func(items []string, itemFunc func(int) string) ([]int, error) {
+		return nil, fmt.Errorf("abort")
+	}
+
+	output, err := executeCommand(t, "discover")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "No servers selected") {
+		t.Errorf("Expected no servers to be selected, got:\n%s", output)
+	}
+	local, _ := config.LoadLocalConfig()
+	if len(local.Private) != 0 {
+		t.Errorf("Expected nothing to be written, got %v", local.Private)
+	}
+}
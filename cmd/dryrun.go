@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"gopkg.in/yaml.v3"
+)
+
+type printMode int
+
+const (
+	printNone printMode = iota
+	printDryRun
+	printCommand
+)
+
+func isPrintFlag(arg string) bool {
+	return arg == "--dry-run" || arg == "--print"
+}
+
+// extractPrintFlags removes --dry-run and --print from args. Arguments after
+// "--" belong to the remote command and are left untouched.
+func extractPrintFlags(args []string) ([]string, printMode) {
+	mode := printNone
+	remaining := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			remaining = append(remaining, args[i:]...)
+			break
+		}
+		switch arg {
+		case "--dry-run":
+			if mode == printNone {
+				mode = printDryRun
+			}
+		case "--print":
+			mode = printCommand
+		default:
+			remaining = append(remaining, arg)
+		}
+	}
+	return remaining, mode
+}
+
+// printInvocation shows what would be executed instead of running it.
+// --print emits only the shell-quoted command line so it can be reused in
+// scripts; --dry-run also shows the effective server record and the argv.
+func printInvocation(w io.Writer, mode printMode, target *sshcmd.Target, inv sshcmd.Invocation) error {
+	if mode == printCommand {
+		_, err := fmt.Fprintln(w, inv.String())
+		return err
+	}
+
+	if target != nil {
+		data, err := yaml.Marshal(target.Server)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "Server:")
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+		if len(target.Hops) > 0 {
+			fmt.Fprintln(w, "Jump hosts:")
+			for _, hop := range target.Hops {
+				hostPort := sshcmd.UserHost(hop, hop.User)
+				if hop.Port != 0 {
+					hostPort = fmt.Sprintf("%s:%d", hostPort, hop.Port)
+				}
+				fmt.Fprintf(w, "  %s (%s)\n", hop.Name, hostPort)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "Argv:")
+	for i, arg := range inv.Argv() {
+		fmt.Fprintf(w, "  [%d] %s\n", i, arg)
+	}
+	fmt.Fprintln(w, "Command:")
+	_, err := fmt.Fprintf(w, "  %s\n", inv.String())
+	return err
+}
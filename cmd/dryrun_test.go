@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestExtractPrintFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedArgs []string
+		expectedMode printMode
+	}{
+		{"no flags", []string{"web", "-v"}, []string{"web", "-v"}, printNone},
+		{"dry run", []string{"web", "--dry-run", "-v"}, []string{"web", "-v"}, printDryRun},
+		{"print", []string{"--print", "web"}, []string{"web"}, printCommand},
+		{"print wins", []string{"--dry-run", "web", "--print"}, []string{"web"}, printCommand},
+		{"after separator", []string{"web", "--", "tool", "--dry-run"}, []string{"web", "--", "tool", "--dry-run"}, printNone},
+		{"empty", []string{}, []string{}, printNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, mode := extractPrintFlags(tt.args)
+			if strings.Join(args, "|") != strings.Join(tt.expectedArgs, "|") {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+			if mode != tt.expectedMode {
+				t.Errorf("Expected mode %v, got %v", tt.expectedMode, mode)
+			}
+		})
+	}
+}
+
+func TestConnectCmd_DryRun(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "bastion", Host: "bastion.example.com", User: "ops", Port: 2222},
+		{Name: "db", Host: "db.internal", User: "admin", Jump: models.JumpChain{"bastion"}, Tags: []string{"prod"}},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	var out bytes.Buffer
+	connectCmd.SetOut(&out)
+	defer connectCmd.SetOut(nil)
+
+	if err := connectCmd.RunE(connectCmd, []string{"db", "--dry-run", "--", "uptime"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mock.LastCommand != "" {
+		t.Errorf("Expected nothing to run, got %s %v", mock.LastCommand, mock.LastArgs)
+	}
+	for _, expected := range []string{
+		"Server:\n  name: db\n  host: db.internal\n",
+		"Jump hosts:\n  bastion (ops@bastion.example.com:2222)\n",
+		"  [0] ssh\n",
+		"  [3] admin@db.internal\n  [4] uptime\n",
+		"Command:\n  ssh -o 'ProxyCommand=ssh -p 2222 -W %h:%p ops@bastion.example.com' admin@db.internal uptime\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestConnectCmd_PrintFromBareName(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web", Host: "web.internal", User: "deploy", Port: 2200},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	var out bytes.Buffer
+	connectCmd.SetOut(&out)
+	defer connectCmd.SetOut(nil)
+
+	ExecuteWithArgs([]string{"sshy", "--print", "web", "-v"})
+
+	if mock.LastCommand != "" {
+		t.Errorf("Expected nothing to run, got %s %v", mock.LastCommand, mock.LastArgs)
+	}
+	if out.String() != "ssh -p 2200 -v deploy@web.internal\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+func TestScpCmd_Print(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web", Host: "web.internal", User: "deploy", Port: 2200},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	var out bytes.Buffer
+	scpCmd.SetOut(&out)
+	defer scpCmd.SetOut(nil)
+
+	if err := scpCmd.RunE(scpCmd, []string{"--print", "web:/var/log/app.log", "./app.log"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mock.LastCommand != "" {
+		t.Errorf("Expected nothing to run, got %s %v", mock.LastCommand, mock.LastArgs)
+	}
+	if out.String() != "scp -P 2200 deploy@web.internal:/var/log/app.log ./app.log\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+func TestSftpCmd_DryRun(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web", Host: "web.internal", User: "deploy"},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	var out bytes.Buffer
+	sftpCmd.SetOut(&out)
+	defer sftpCmd.SetOut(nil)
+
+	if err := sftpCmd.RunE(sftpCmd, []string{"web", "--dry-run"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mock.LastCommand != "" {
+		t.Errorf("Expected nothing to run, got %s %v", mock.LastCommand, mock.LastArgs)
+	}
+	if !strings.Contains(out.String(), "Command:\n  sftp deploy@web.internal\n") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/spf13/cobra"
+)
+
+type hostResult struct {
+	Name       string        `json:"name"`
+	Host       string        `json:"host"`
+	ExitCode   int           `json:"exit_code"`
+	Duration   time.Duration `json:"-"`
+	DurationMS int64         `json:"duration_ms"`
+	Stdout     string        `json:"stdout,omitempty"`
+	Stderr     string        `json:"stderr,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+const (
+	errNotStarted  = "not started"
+	errInterrupted = "interrupted"
+)
+
+func (r hostResult) failed() bool {
+	return r.ExitCode != 0 || r.Error != ""
+}
+
+// finished reports whether the command ran to completion on the server,
+// successfully or not.
+func (r hostResult) finished() bool {
+	return r.Error != errNotStarted && r.Error != errInterrupted
+}
+
+type execOptions struct {
+	Concurrency int
+	Timeout     time.Duration
+	// Capture collects output into the results instead of streaming it.
+	Capture bool
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec [names...] [--tags tags] -- <command>",
+	Short: "Run a command on several servers in parallel",
+	Long: `Run a remote command on every selected server in parallel.
+
+Servers are selected by name, by --tags (servers having all of the tags) or
+with --all. Output lines are prefixed with the server name, and a summary of
+exit codes and durations is printed at the end. The command exits non-zero if
+any server failed.
+
+With --rolling, servers are run in batches of --batch-size (a count or a
+percentage), each batch all at once, optionally starting with a single --canary server and pausing
+between batches. --fail-fast and --max-failures stop the rollout early. Servers
+that were never reached are listed at the end.`,
+	Example: `  sshy exec --tags prod,web -- uptime
+  sshy exec --tags web --rolling --canary --batch-size 25% --pause 30s --fail-fast -- sudo systemctl restart nginx
+  sshy exec web-1 web-2 --timeout 30s -- systemctl is-active nginx
+  sshy exec --tags db --format json -- df -h /`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dash := cmd.ArgsLenAtDash()
+		if dash < 0 || dash >= len(args) {
+			return fmt.Errorf("usage: sshy exec [names...] [--tags tags] -- <command>")
+		}
+		names, command := args[:dash], strings.Join(args[dash:], " ")
+
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q: use text or json", format)
+		}
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		tags, all := selectionFromFlags(cmd)
+		rolling, err := rollingFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		inventory, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		selected, err := selectServers(inventory, names, tags, all)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		opts := execOptions{Concurrency: concurrency, Timeout: timeout, Capture: format == "json"}
+		progress := cmd.OutOrStdout()
+		if format == "json" {
+			progress = cmd.ErrOrStderr()
+		}
+
+		var results []hostResult
+		if rolling != nil {
+			results, err = runRolling(ctx, inventory, selected, command, opts, *rolling, cmd.OutOrStdout(), cmd.ErrOrStderr(), progress)
+			if err != nil {
+				return err
+			}
+		} else {
+			results = runOnServers(ctx, inventory, selected, command, opts, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		}
+
+		if format == "json" {
+			if err := writeResultsJSON(cmd.OutOrStdout(), results); err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout())
+			printResultsTable(cmd.OutOrStdout(), results)
+		}
+		printProgressReport(progress, results)
+		return resultsError(results)
+	},
+}
+
+// runOnServers runs command on servers and returns one result per server, in
+// the same order.
+func runOnServers(ctx context.Context, inventory, servers models.Servers, command string, opts execOptions, stdout, stderr io.Writer) []hostResult {
+	results := make([]hostResult, len(servers))
+	width := 0
+	for _, s := range servers {
+		width = max(width, len(s.Name))
+	}
+
+	var mu sync.Mutex
+	forEachServer(ctx, servers, opts.Concurrency, func(ctx context.Context, i int, s models.Server) {
+		if opts.Capture {
+			var outBuf, errBuf bytes.Buffer
+			results[i] = runRemoteCommand(ctx, inventory, s, command, opts.Timeout, &outBuf, &errBuf)
+			results[i].Stdout = outBuf.String()
+			results[i].Stderr = errBuf.String()
+			return
+		}
+		prefix := fmt.Sprintf("%-*s | ", width, s.Name)
+		outWriter := newPrefixWriter(&mu, stdout, prefix)
+		errWriter := newPrefixWriter(&mu, stderr, prefix)
+		results[i] = runRemoteCommand(ctx, inventory, s, command, opts.Timeout, outWriter, errWriter)
+		outWriter.Flush()
+		errWriter.Flush()
+	})
+
+	for i, s := range servers {
+		if results[i].Name == "" {
+			results[i] = hostResult{Name: s.Name, Host: s.Host, ExitCode: -1, Error: errNotStarted}
+		}
+	}
+	return results
+}
+
+func runRemoteCommand(ctx context.Context, inventory models.Servers, s models.Server, command string, timeout time.Duration, stdout, stderr io.Writer) hostResult {
+	result := hostResult{Name: s.Name, Host: s.Host}
+	start := time.Now()
+
+	target, err := sshcmd.NewTarget(s, inventory)
+	if err != nil {
+		return failedResult(result, start, err)
+	}
+	inv, err := sshcmd.SSH(target, []string{"-T", "-o", "BatchMode=yes"}, command)
+	if err != nil {
+		return failedResult(result, start, err)
+	}
+	return runInvocation(ctx, result, start, inv, timeout, stdout, stderr)
+}
+
+// runInvocation runs inv for the server in result and records how it ended.
+func runInvocation(ctx context.Context, result hostResult, start time.Time, inv sshcmd.Invocation, timeout time.Duration, stdout, stderr io.Writer) hostResult {
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err := cmdRunner.RunContext(runCtx, inv.Program, inv.Args, stdout, stderr)
+	result.ExitCode, result.Error = exitStatus(runCtx, err)
+	result.Duration = time.Since(start)
+	result.DurationMS = result.Duration.Milliseconds()
+	return result
+}
+
+func failedResult(result hostResult, start time.Time, err error) hostResult {
+	result.ExitCode = -1
+	result.Error = err.Error()
+	result.Duration = time.Since(start)
+	result.DurationMS = result.Duration.Milliseconds()
+	return result
+}
+
+// exitStatus turns the error of a finished command into an exit code and, for
+// failures that are not a plain non-zero exit, a description.
+func exitStatus(ctx context.Context, err error) (int, string) {
+	if err == nil {
+		return 0, ""
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return -1, "timed out"
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return -1, errInterrupted
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) && coder.ExitCode() >= 0 {
+		return coder.ExitCode(), ""
+	}
+	return -1, err.Error()
+}
+
+func printResultsTable(w io.Writer, results []hostResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tHOST\tEXIT\tDURATION\tERROR")
+	failed := 0
+	for _, r := range results {
+		if r.failed() {
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", r.Name, r.Host, r.ExitCode, r.Duration.Round(time.Millisecond), r.Error)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d succeeded, %d failed\n", len(results)-failed, failed)
+}
+
+func writeResultsJSON(w io.Writer, results []hostResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+func resultsError(results []hostResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.failed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed on %d of %d servers", failed, len(results))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	addSelectionFlags(execCmd)
+	execCmd.Flags().IntP("concurrency", "c", 10, "Maximum number of servers to run on at once")
+	execCmd.Flags().Duration("timeout", 0, "Per-server timeout, e.g. 30s (0 means no timeout)")
+	execCmd.Flags().String("format", "text", "Output format: text or json")
+
+	execCmd.Flags().Bool("rolling", false, "Run in batches instead of all at once")
+	execCmd.Flags().String("batch-size", "1", "Servers per batch in rolling mode, as a count or a percentage (e.g. 25%)")
+	execCmd.Flags().Duration("pause", 0, "Pause between batches in rolling mode, e.g. 30s")
+	execCmd.Flags().Bool("canary", false, "Run the first server alone before the first batch and stop if it fails")
+	execCmd.Flags().Bool("fail-fast", false, "Stop after the first batch with a failure")
+	execCmd.Flags().Int("max-failures", 0, "Stop once this many servers have failed (0 means no limit)")
+}
+
+// rollingFromFlags returns nil unless rolling mode is requested. Any of the
+// batch flags implies --rolling.
+func rollingFromFlags(cmd *cobra.Command) (*rollingOptions, error) {
+	rolling, _ := cmd.Flags().GetBool("rolling")
+	for _, name := range []string{"batch-size", "pause", "canary", "fail-fast", "max-failures"} {
+		if cmd.Flags().Changed(name) {
+			rolling = true
+		}
+	}
+	if !rolling {
+		return nil, nil
+	}
+
+	opts := &rollingOptions{}
+	opts.BatchSize, _ = cmd.Flags().GetString("batch-size")
+	opts.Pause, _ = cmd.Flags().GetDuration("pause")
+	opts.Canary, _ = cmd.Flags().GetBool("canary")
+	opts.FailFast, _ = cmd.Flags().GetBool("fail-fast")
+	opts.MaxFailures, _ = cmd.Flags().GetInt("max-failures")
+	if opts.MaxFailures < 0 {
+		return nil, fmt.Errorf("--max-failures must not be negative")
+	}
+	if _, err := parseBatchSize(opts.BatchSize, 1); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
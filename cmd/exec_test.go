@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func execTestServers() models.Servers {
+	return models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"prod", "web"}},
+		{Name: "web-2", Host: "10.0.0.2", User: "deploy", Tags: []string{"prod", "web"}},
+		{Name: "db-1", Host: "10.0.1.1", User: "admin", Tags: []string{"prod", "db"}},
+	}
+}
+
+func TestExecCmd(t *testing.T) {
+	setupTestServers(t, execTestServers())
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			switch args[len(args)-2] {
+			case "deploy@10.0.0.1":
+				fmt.Fprint(stdout, "load average: 0.10\nsecond line")
+				return nil
+			case "deploy@10.0.0.2":
+				fmt.Fprintln(stderr, "uptime: not found")
+				return mockExitError(127)
+			}
+			return fmt.Errorf("unexpected host %v", args)
+		},
+	}
+	cmdRunner = mock
+
+	out, err := executeCommand(t, "exec", "--tags", "web", "--", "uptime", "-p")
+	if err == nil || !strings.Contains(err.Error(), "failed on 1 of 2 servers") {
+		t.Errorf("Expected failure summary error, got %v", err)
+	}
+
+	for _, expected := range []string{
+		"web-1 | load average: 0.10\n",
+		"web-1 | second line\n",
+		"web-2 | uptime: not found\n",
+		"1 succeeded, 1 failed",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "db-1") {
+		t.Errorf("Expected db-1 not to be selected, got:\n%s", out)
+	}
+
+	if len(mock.Calls) != 2 {
+		t.Fatalf("Expected 2 ssh calls, got %d", len(mock.Calls))
+	}
+	for _, call := range mock.Calls {
+		if call[0] != "ssh" || call[len(call)-1] != "uptime -p" || !containsSequence(call, "-o", "BatchMode=yes") {
+			t.Errorf("Unexpected call: %v", call)
+		}
+	}
+}
+
+func TestExecCmd_JSON(t *testing.T) {
+	setupTestServers(t, execTestServers())
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	cmdRunner = &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			fmt.Fprintln(stdout, "ok")
+			return nil
+		},
+	}
+
+	out, err := executeCommand(t, "exec", "db-1", "--format", "json", "--", "true")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var results []hostResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("Expected JSON output, got %v:\n%s", err, out)
+	}
+	if len(results) != 1 || results[0].Name != "db-1" || results[0].ExitCode != 0 || results[0].Stdout != "ok\n" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+func TestExecCmd_RequiresCommandAndSelection(t *testing.T) {
+	setupTestServers(t, execTestServers())
+
+	if _, err := executeCommand(t, "exec", "--tags", "web"); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("Expected usage error without command, got %v", err)
+	}
+	if _, err := executeCommand(t, "exec", "--", "uptime"); err == nil || !strings.Contains(err.Error(), "no servers selected") {
+		t.Errorf("Expected selection error, got %v", err)
+	}
+	if _, err := executeCommand(t, "exec", "--tags", "web", "--format", "xml", "--", "uptime"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestRunOnServers_ConcurrencyAndTimeout(t *testing.T) {
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	cmdRunner = &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+			if args[len(args)-2] == "slow.internal" {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		},
+	}
+
+	servers := models.Servers{
+		{Name: "a", Host: "a.internal"},
+		{Name: "b", Host: "b.internal"},
+		{Name: "slow", Host: "slow.internal"},
+		{Name: "c", Host: "c.internal"},
+	}
+	opts := execOptions{Concurrency: 2, Timeout: 50 * time.Millisecond, Capture: true}
+	results := runOnServers(context.Background(), servers, servers, "true", opts, io.Discard, io.Discard)
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent runs, got %d", peak)
+	}
+	for i, r := range results {
+		if r.Name != servers[i].Name {
+			t.Errorf("Expected results in server order, got %s at %d", r.Name, i)
+		}
+	}
+	if results[2].Error != "timed out" || !results[2].failed() {
+		t.Errorf("Expected slow server to time out, got %+v", results[2])
+	}
+	if results[0].failed() || results[3].failed() {
+		t.Errorf("Expected other servers to succeed, got %+v", results)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		err          error
+		expectedCode int
+		expectedErr  string
+	}{
+		{"success", context.Background(), nil, 0, ""},
+		{"exit code", context.Background(), mockExitError(3), 3, ""},
+		{"other error", context.Background(), fmt.Errorf("exec: not found"), -1, "exec: not found"},
+		{"interrupted", cancelled, fmt.Errorf("signal: killed"), -1, "interrupted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, msg := exitStatus(tt.ctx, tt.err)
+			if code != tt.expectedCode || msg != tt.expectedErr {
+				t.Errorf("exitStatus() = %d, %q, expected %d, %q", code, msg, tt.expectedCode, tt.expectedErr)
+			}
+		})
+	}
+}
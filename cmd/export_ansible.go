@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/omisai-tech/sshy/internal/inventory"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var exportAnsibleCmd = &cobra.Command{
+	Use:   "ansible",
+	Short: "Export servers as an Ansible inventory",
+	Long: `Export servers as an Ansible inventory in INI or YAML format.
+
+Tags become groups; servers without tags are put in "ungrouped". The host
+name is the sshy name, with ansible_host, ansible_user, ansible_port and
+ansible_ssh_private_key_file set from the server. SSH options and jump hosts
+are passed in ansible_ssh_common_args.`,
+	Example: `  sshy export ansible > hosts.ini
+  sshy export ansible --tags prod --format yaml --output inventory/prod.yml`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+
+		inventoryServers, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		var servers models.Servers
+		for _, s := range inventoryServers {
+			if hasAllTags(s.Tags, tags) {
+				servers = append(servers, s)
+			}
+		}
+
+		hosts, err := inventory.AnsibleHosts(servers, inventoryServers)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		switch format {
+		case "ini":
+			err = inventory.WriteAnsibleINI(&buf, hosts)
+		case "yaml":
+			err = inventory.WriteAnsibleYAML(&buf, hosts)
+		default:
+			return fmt.Errorf("unknown format %q: use ini or yaml", format)
+		}
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			_, err = cmd.OutOrStdout().Write(buf.Bytes())
+			return err
+		}
+		if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d servers to %s\n", len(hosts), output)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportAnsibleCmd)
+
+	exportAnsibleCmd.Flags().String("format", "ini", "Inventory format: ini or yaml")
+	exportAnsibleCmd.Flags().StringP("output", "o", "", "Write to this file instead of printing")
+	exportAnsibleCmd.Flags().StringSliceP("tags", "t", []string{}, "Only export servers having all of these tags")
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/inventory"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var exportCSVCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Export servers as CSV",
+	Long: `Export the merged inventory as CSV, one row per server.
+
+The first column is the source of each server, as in "sshy list": [S] for
+shared servers, [L] for local private servers and [O] for shared servers
+with local overrides. It is followed by the server fields and one
+option.<Keyword> column per SSH option in use. The file can be imported
+again with "sshy import csv", which ignores the source column.`,
+	Example: `  sshy export csv > servers.csv
+  sshy export csv --tags prod --column name=Hostname --output prod.csv`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := csvOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		output, _ := cmd.Flags().GetString("output")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+
+		cfg, err := config.LoadGlobalConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+		serversWithSource, err := config.LoadServersWithSourceFromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("error loading servers: %w", err)
+		}
+		var filtered []models.ServerWithSource
+		for _, sws := range serversWithSource {
+			if hasAllTags(sws.Server.Tags, tags) {
+				filtered = append(filtered, sws)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := inventory.WriteCSV(&buf, filtered, opts); err != nil {
+			return err
+		}
+		if output == "" {
+			_, err = cmd.OutOrStdout().Write(buf.Bytes())
+			return err
+		}
+		if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d servers to %s\n", len(filtered), output)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportCSVCmd)
+
+	addCSVFlags(exportCSVCmd)
+	exportCSVCmd.Flags().StringP("output", "o", "", "Write to this file instead of printing")
+	exportCSVCmd.Flags().StringSliceP("tags", "t", []string{}, "Only export servers having all of these tags")
+}
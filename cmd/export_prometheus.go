@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/inventory"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var exportPrometheusCmd = &cobra.Command{
+	Use:   "prometheus",
+	Short: "Export servers as Prometheus file_sd targets",
+	Long: `Export the merged inventory as a Prometheus file_sd document, with every
+server as a host:port target.
+
+Servers with the same tags and source share a target group labelled with
+sshy_source (shared, local or override), sshy_tags (such as ",prod,web,")
+and sshy_tag_<tag>="true" per tag. With --group-by server every server gets
+its own group, which also carries sshy_name.
+
+With --watch, the inventory is read again every --interval and --output is
+rewritten whenever the targets change, whether servers.yaml, local.yaml or
+the content behind servers_url changed.`,
+	Example: `  sshy export prometheus --port 9100 --output /etc/prometheus/sshy.json
+  sshy export prometheus --tags prod --group-by server --output targets.json --watch`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		port, _ := cmd.Flags().GetInt("port")
+		output, _ := cmd.Flags().GetString("output")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		watch, _ := cmd.Flags().GetBool("watch")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("invalid --port %d", port)
+		}
+		if groupBy != "tags" && groupBy != "server" {
+			return fmt.Errorf("unknown --group-by %q: use tags or server", groupBy)
+		}
+		if watch && output == "" {
+			return fmt.Errorf("--watch needs --output")
+		}
+		if watch && interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		render := func() ([]byte, error) {
+			return renderPrometheusTargets(port, tags, groupBy == "server")
+		}
+
+		if watch {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return watchTargets(ctx, interval, render, output, cmd.ErrOrStderr())
+		}
+
+		data, err := render()
+		if err != nil {
+			return err
+		}
+		if output == "" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+		return writeFileAtomic(output, data, 0644)
+	},
+}
+
+func renderPrometheusTargets(port int, tags []string, perServer bool) ([]byte, error) {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+	serversWithSource, err := config.LoadServersWithSourceFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error loading servers: %w", err)
+	}
+	var filtered []models.ServerWithSource
+	for _, sws := range serversWithSource {
+		if hasAllTags(sws.Server.Tags, tags) {
+			filtered = append(filtered, sws)
+		}
+	}
+	return inventory.MarshalTargets(inventory.PrometheusTargets(filtered, port, perServer))
+}
+
+// watchTargets renders the targets every interval and rewrites output when
+// they change, until ctx is done. Errors are logged and the previous file
+// is kept, so a flaky servers_url does not empty the target list.
+func watchTargets(ctx context.Context, interval time.Duration, render func() ([]byte, error), output string, log io.Writer) error {
+	last, _ := os.ReadFile(output)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		data, err := render()
+		switch {
+		case err != nil:
+			fmt.Fprintf(log, "%s error: %v\n", time.Now().Format(time.RFC3339), err)
+		case !bytes.Equal(data, last):
+			if err := writeFileAtomic(output, data, 0644); err != nil {
+				fmt.Fprintf(log, "%s error: %v\n", time.Now().Format(time.RFC3339), err)
+				break
+			}
+			last = data
+			fmt.Fprintf(log, "%s wrote %s\n", time.Now().Format(time.RFC3339), output)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	exportCmd.AddCommand(exportPrometheusCmd)
+
+	exportPrometheusCmd.Flags().Int("port", 9100, "Port to scrape on every server")
+	exportPrometheusCmd.Flags().StringP("output", "o", "", "Write to this file instead of printing")
+	exportPrometheusCmd.Flags().StringSliceP("tags", "t", []string{}, "Only export servers having all of these tags")
+	exportPrometheusCmd.Flags().String("group-by", "tags", "One target group per tag combination (tags) or per server (server)")
+	exportPrometheusCmd.Flags().Bool("watch", false, "Keep running and rewrite --output whenever the targets change")
+	exportPrometheusCmd.Flags().Duration("interval", 10*time.Second, "How often to read the inventory in --watch mode")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestExportPrometheusCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", Tags: []string{"web"}},
+		{Name: "db-1", Host: "10.0.1.1", Tags: []string{"db"}},
+	})
+
+	output, err := executeCommand(t, "export", "prometheus", "--port", "9187", "--tags", "db")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, `"10.0.1.1:9187"`) || strings.Contains(output, "10.0.0.1") {
+		t.Errorf("Expected only db-1 on port 9187, got:\n%s", output)
+	}
+
+	if _, err := executeCommand(t, "export", "prometheus", "--watch"); err == nil {
+		t.Errorf("Expected --watch without --output to fail")
+	}
+}
+
+func TestWatchTargets(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "targets.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	contents := [][]byte{[]byte("a\n"), nil, []byte("a\n"), []byte("b\n")}
+	renders := 0
+	render := func() ([]byte, error) {
+		if renders >= len(contents) {
+			cancel()
+			return contents[len(contents)-1], nil
+		}
+		data := contents[renders]
+		renders++
+		if data == nil {
+			return nil, errors.New("fetch failed")
+		}
+		return data, nil
+	}
+
+	var log bytes.Buffer
+	done := make(chan error)
+	go func() { done <- watchTargets(ctx, time.Millisecond, render, output, &log) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchTargets did not stop")
+	}
+
+	data, _ := os.ReadFile(output)
+	if string(data) != "b\n" {
+		t.Errorf("Expected the last targets, got %q", data)
+	}
+	if strings.Count(log.String(), "wrote") != 2 || !strings.Contains(log.String(), "fetch failed") {
+		t.Errorf("Expected two writes and one error, got:\n%s", log.String())
+	}
+}
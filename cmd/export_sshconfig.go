@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshconfig"
+	"github.com/spf13/cobra"
+)
+
+var exportSSHConfigCmd = &cobra.Command{
+	Use:   "ssh-config",
+	Short: "Export servers as ssh_config Host blocks",
+	Long: `Export the merged inventory as ssh_config Host blocks so that ssh, git,
+VS Code Remote and other OpenSSH clients can reach servers by their sshy name.
+
+By default the blocks are printed. --output writes them to a standalone file
+to be Included from ~/.ssh/config. --managed-block instead keeps them in a
+marker-delimited block inside ~/.ssh/config (or the --output file), updated
+in place without touching the rest of the file. --check only compares and
+exits non-zero when the file is out of date.`,
+	Example: `  sshy export ssh-config --output ~/.ssh/sshy.conf
+  sshy export ssh-config --managed-block
+  sshy export ssh-config --managed-block --check`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		managed, _ := cmd.Flags().GetBool("managed-block")
+		check, _ := cmd.Flags().GetBool("check")
+
+		if managed && output == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			output = filepath.Join(home, ".ssh", "config")
+		}
+		if check && output == "" {
+			return fmt.Errorf("--check needs --output or --managed-block")
+		}
+
+		cfg, err := config.LoadGlobalConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+		serversWithSource, err := config.LoadServersWithSourceFromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("error loading servers: %w", err)
+		}
+		servers := make(models.Servers, len(serversWithSource))
+		for i, sws := range serversWithSource {
+			servers[i] = sws.Server
+		}
+
+		rendered, err := sshconfig.Render(servers)
+		if err != nil {
+			return err
+		}
+		if output == "" {
+			fmt.Fprint(cmd.OutOrStdout(), rendered)
+			return nil
+		}
+
+		current, err := os.ReadFile(output)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		var content string
+		if managed {
+			content, err = sshconfig.ReplaceBlock(string(current), sshconfig.ManagedBlock(rendered))
+			if err != nil {
+				return fmt.Errorf("%s: %w", output, err)
+			}
+		} else {
+			content = "# Generated by sshy. Changes here are overwritten; run \"sshy export ssh-config\" to update.\n\n" + rendered
+		}
+
+		if content == string(current) {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", output)
+			return nil
+		}
+		if check {
+			return fmt.Errorf("%s is out of date; run 'sshy export ssh-config' to update it", output)
+		}
+		if err := writeFileAtomic(output, []byte(content), 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d servers to %s\n", len(servers), output)
+		if !managed {
+			fmt.Fprintf(cmd.OutOrStdout(), "Add 'Include %s' at the top of ~/.ssh/config to use them\n", output)
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportSSHConfigCmd)
+
+	exportSSHConfigCmd.Flags().StringP("output", "o", "", "Write to this file instead of printing")
+	exportSSHConfigCmd.Flags().Bool("managed-block", false, "Update a managed block inside ~/.ssh/config (or --output) instead of the whole file")
+	exportSSHConfigCmd.Flags().Bool("check", false, "Exit non-zero if the file is out of date instead of writing it")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestExportSSHConfigCmd_Stdout(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy"},
+	})
+
+	output, err := executeCommand(t, "export", "ssh-config")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "Host web-1\n  HostName 10.0.0.1\n  User deploy\n") {
+		t.Errorf("Expected a Host block for web-1, got:\n%s", output)
+	}
+}
+
+func TestExportSSHConfigCmd_ManagedBlock(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy"},
+	})
+	path := writeSSHConfig(t, "Host github.com\n  User git\n")
+
+	if _, err := executeCommand(t, "export", "ssh-config", "--managed-block", "--check"); err == nil {
+		t.Errorf("Expected --check to fail before the block is written")
+	}
+	if _, err := executeCommand(t, "export", "ssh-config", "--managed-block"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if !strings.HasPrefix(content, "# BEGIN sshy managed block\n") {
+		t.Errorf("Expected the managed block at the top, got:\n%s", content)
+	}
+	if !strings.Contains(content, "Host web-1\n") || !strings.HasSuffix(content, "Host github.com\n  User git\n") {
+		t.Errorf("Expected web-1 and the existing entries, got:\n%s", content)
+	}
+
+	output, err := executeCommand(t, "export", "ssh-config", "--managed-block", "--check")
+	if err != nil {
+		t.Errorf("Expected --check to pass after writing, got %v", err)
+	}
+	if !strings.Contains(output, "is up to date") {
+		t.Errorf("Expected up to date message, got:\n%s", output)
+	}
+
+	setupTestServers(t, models.Servers{
+		{Name: "web-2", Host: "10.0.0.2"},
+	})
+	path = writeSSHConfig(t, content)
+	if _, err := executeCommand(t, "export", "ssh-config", "--managed-block"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "web-1") || !strings.Contains(string(data), "Host web-2\n") {
+		t.Errorf("Expected the block to be replaced, got:\n%s", data)
+	}
+	if strings.Count(string(data), "# BEGIN sshy managed block") != 1 {
+		t.Errorf("Expected a single managed block, got:\n%s", data)
+	}
+}
+
+func TestExportSSHConfigCmd_Output(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1"},
+	})
+	home, _ := os.UserHomeDir()
+	path := filepath.Join(home, ".ssh", "sshy.conf")
+
+	output, err := executeCommand(t, "export", "ssh-config", "--output", path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "Include "+path) {
+		t.Errorf("Expected an Include hint, got:\n%s", output)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected %s to be written: %v", path, err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestWriteFileAtomic_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "ssh_config")
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("old\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "config")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(link, []byte("new\n"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Expected the symlink to stay, got %v, %v", info, err)
+	}
+	data, _ := os.ReadFile(target)
+	if string(data) != "new\n" {
+		t.Errorf("Expected the target to be written, got %q", data)
+	}
+	if info, _ := os.Stat(target); info.Mode().Perm() != 0640 {
+		t.Errorf("Expected the target to keep mode 0640, got %v", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/facts"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var factsCmd = &cobra.Command{
+	Use:   "facts [names...]",
+	Short: "Collect and show facts about servers",
+	Long: `Connect to the selected servers and collect facts: OS release, kernel, uptime,
+CPU count, memory, disk usage and IP addresses. Facts are cached per server in
+~/.sshy/facts, so list --facts, info and the connect picker can show them
+without reconnecting.
+
+Only servers without cached facts, or with facts older than --max-age, are
+contacted; use --refresh to collect from every selected server. Without names,
+--tags or --all, every server is selected.`,
+	Example: `  sshy facts
+  sshy facts --tags prod --refresh
+  sshy facts --format json | jq -r '.[] | select(.facts.kernel | test("^Linux 4\\.")) | .name'`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		maxAge, _ := cmd.Flags().GetDuration("max-age")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q: use text or json", format)
+		}
+		tags, all := selectionFromFlags(cmd)
+
+		inventory, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 && len(tags) == 0 {
+			all = true
+		}
+		selected, err := selectServers(inventory, args, tags, all)
+		if err != nil {
+			return err
+		}
+		cache, err := factsCache()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		cached := make([]*facts.Facts, len(selected))
+		var outdated models.Servers
+		var outdatedIndex []int
+		for i, s := range selected {
+			f, found, err := cache.Load(s.Name)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Ignoring cached facts for %s: %v\n", s.Name, err)
+			}
+			if found {
+				cached[i] = &f
+			}
+			if refresh || !found || f.Stale(maxAge, now) {
+				outdated = append(outdated, s)
+				outdatedIndex = append(outdatedIndex, i)
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		failed := 0
+		if len(outdated) > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Collecting facts from %d server(s)...\n", len(outdated))
+			collected, errs := collectFacts(ctx, inventory, outdated, cache, concurrency, timeout)
+			for i, s := range outdated {
+				if errs[i] != nil {
+					failed++
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", s.Name, errs[i])
+					continue
+				}
+				cached[outdatedIndex[i]] = &collected[i]
+			}
+		}
+
+		if format == "json" {
+			if err := writeFactsJSON(cmd.OutOrStdout(), selected, cached); err != nil {
+				return err
+			}
+		} else {
+			printFactsTable(cmd.OutOrStdout(), selected, cached, time.Now())
+		}
+		if failed > 0 {
+			return fmt.Errorf("collecting facts failed on %d of %d servers", failed, len(outdated))
+		}
+		return nil
+	},
+}
+
+func factsCache() (*facts.Cache, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return facts.NewCache(filepath.Join(dir, "facts")), nil
+}
+
+// collectFacts runs the facts probe on servers and caches what it collects.
+// It returns the facts and an error per server, in the same order.
+func collectFacts(ctx context.Context, inventory, servers models.Servers, cache *facts.Cache, concurrency int, timeout time.Duration) ([]facts.Facts, []error) {
+	opts := execOptions{Concurrency: concurrency, Timeout: timeout, Capture: true}
+	results := runOnServers(ctx, inventory, servers, facts.Command(), opts, io.Discard, io.Discard)
+
+	collected := make([]facts.Facts, len(servers))
+	errs := make([]error, len(servers))
+	for i, r := range results {
+		if r.failed() {
+			switch detail := lastLine(r.Stderr); {
+			case r.Error != "":
+				errs[i] = fmt.Errorf("%s", r.Error)
+			case detail != "":
+				errs[i] = fmt.Errorf("%s", detail)
+			default:
+				errs[i] = fmt.Errorf("exit code %d", r.ExitCode)
+			}
+			continue
+		}
+		f, err := facts.Parse(r.Stdout)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		f.CollectedAt = time.Now()
+		if err := cache.Save(servers[i].Name, f); err != nil {
+			errs[i] = fmt.Errorf("error saving facts: %w", err)
+			continue
+		}
+		collected[i] = f
+	}
+	return collected, errs
+}
+
+func printFactsTable(w io.Writer, servers models.Servers, cached []*facts.Facts, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tOS\tKERNEL\tCPUS\tMEMORY\tROOT\tUPTIME\tCOLLECTED")
+	for i, s := range servers {
+		f := cached[i]
+		if f == nil {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t-\t-\tnever\n", s.Name)
+			continue
+		}
+		root := "-"
+		for _, d := range f.Disks {
+			if d.Mount == "/" {
+				root = fmt.Sprintf("%d%% of %s", d.UsedPercent(), facts.FormatBytes(d.SizeBytes))
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			s.Name, orDash(f.OS), orDash(f.Kernel), f.CPUs, facts.FormatBytes(f.MemoryTotalBytes),
+			root, formatDuration(f.Uptime), formatAge(now.Sub(f.CollectedAt)))
+	}
+	tw.Flush()
+}
+
+func writeFactsJSON(w io.Writer, servers models.Servers, cached []*facts.Facts) error {
+	type entry struct {
+		Name  string       `json:"name"`
+		Host  string       `json:"host"`
+		Facts *facts.Facts `json:"facts"`
+	}
+	entries := make([]entry, len(servers))
+	for i, s := range servers {
+		entries[i] = entry{Name: s.Name, Host: s.Host, Facts: cached[i]}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// printFactsDetails writes the full facts of a server, as shown by info and
+// the picker preview.
+func printFactsDetails(w io.Writer, f facts.Facts, now time.Time) {
+	fmt.Fprintf(w, "Collected: %s (%s)\n", f.CollectedAt.Local().Format("2006-01-02 15:04"), formatAge(now.Sub(f.CollectedAt)))
+	fmt.Fprintf(w, "OS:        %s\n", orDash(f.OS))
+	fmt.Fprintf(w, "Kernel:    %s\n", orDash(f.Kernel))
+	fmt.Fprintf(w, "Uptime:    %s\n", formatDuration(f.Uptime))
+	fmt.Fprintf(w, "CPUs:      %d\n", f.CPUs)
+	fmt.Fprintf(w, "Memory:    %s total, %s available\n", facts.FormatBytes(f.MemoryTotalBytes), facts.FormatBytes(f.MemoryAvailableBytes))
+	if len(f.Disks) > 0 {
+		fmt.Fprintln(w, "Disks:")
+		for _, d := range f.Disks {
+			fmt.Fprintf(w, "  %s: %d%% of %s used\n", d.Mount, d.UsedPercent(), facts.FormatBytes(d.SizeBytes))
+		}
+	}
+	if len(f.Addresses) > 0 {
+		fmt.Fprintf(w, "Addresses: %s\n", strings.Join(f.Addresses, ", "))
+	}
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	days := int(d.Hours() / 24)
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, int(d.Hours())%24)
+	}
+	return d.Truncate(time.Minute).String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(factsCmd)
+
+	addSelectionFlags(factsCmd)
+	factsCmd.Flags().Bool("refresh", false, "Collect facts even if the cached ones are fresh")
+	factsCmd.Flags().Duration("max-age", 24*time.Hour, "Collect again when cached facts are older than this (0 keeps them forever)")
+	factsCmd.Flags().IntP("concurrency", "c", 10, "Maximum number of servers to contact at once")
+	factsCmd.Flags().Duration("timeout", 30*time.Second, "Per-server timeout")
+	factsCmd.Flags().String("format", "text", "Output format: text or json")
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/facts"
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+const factsOutput = `==os==
+PRETTY_NAME="Debian GNU/Linux 12 (bookworm)"
+==kernel==
+Linux 6.1.0-18-amd64
+==uptime==
+3600.00 7000.00
+==cpus==
+2
+==memory==
+MemTotal:        4026532 kB
+MemAvailable:    3000000 kB
+==disks==
+Filesystem     1024-blocks     Used Available Capacity Mounted on
+/dev/vda1         20511312  5127828  15383484      25% /
+==addresses==
+10.0.0.1
+==end==
+`
+
+func TestFactsCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"web"}},
+		{Name: "web-2", Host: "10.0.0.2", User: "deploy", Tags: []string{"web"}},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			if args[len(args)-2] == "deploy@10.0.0.2" {
+				fmt.Fprintln(stderr, "Permission denied (publickey).")
+				return mockExitError(255)
+			}
+			fmt.Fprint(stdout, factsOutput)
+			return nil
+		},
+	}
+	cmdRunner = mock
+
+	out, err := executeCommand(t, "facts")
+	if err == nil || !strings.Contains(err.Error(), "failed on 1 of 2 servers") {
+		t.Errorf("Expected collection failure for web-2, got %v", err)
+	}
+	for _, expected := range []string{"Debian GNU/Linux 12 (bookworm)", "Linux 6.1.0-18-amd64", "25% of 19.6 GiB", "web-2: Permission denied", "never"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if len(mock.Calls) != 2 || !strings.HasPrefix(mock.Calls[0][len(mock.Calls[0])-1], "sh -c ") {
+		t.Fatalf("Expected the probe to run on both servers, got %v", mock.Calls)
+	}
+
+	cachePath := filepath.Join(os.Getenv("HOME"), ".sshy", "facts", "web-1.json")
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("Expected facts cached at %s: %v", cachePath, err)
+	}
+
+	mock.Calls = nil
+	if _, err := executeCommand(t, "facts", "web-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mock.Calls) != 0 {
+		t.Errorf("Expected fresh facts to be served from the cache, got %v", mock.Calls)
+	}
+
+	if _, err := executeCommand(t, "facts", "web-1", "--refresh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("Expected --refresh to collect again, got %d calls", len(mock.Calls))
+	}
+	if config.ForceRefresh {
+		t.Error("Expected --refresh to leave the servers lists alone")
+	}
+
+	mock.Calls = nil
+	defer func() { config.ForceRefresh = false }()
+	if _, err := executeCommand(t, "facts", "web-1", "--refresh-servers"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !config.ForceRefresh || len(mock.Calls) != 0 {
+		t.Errorf("Expected --refresh-servers to refresh only the servers lists, got ForceRefresh=%v and %d calls", config.ForceRefresh, len(mock.Calls))
+	}
+}
+
+func TestFactsCmd_StaleEntries(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "web-1", Host: "10.0.0.1"}})
+
+	cache, err := factsCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save("web-1", facts.Facts{CollectedAt: time.Now().Add(-48 * time.Hour), Kernel: "Linux 4.19.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			fmt.Fprint(stdout, factsOutput)
+			return nil
+		},
+	}
+	cmdRunner = mock
+
+	out, err := executeCommand(t, "facts", "--max-age", "72h")
+	if err != nil || len(mock.Calls) != 0 || !strings.Contains(out, "Linux 4.19.0") {
+		t.Errorf("Expected cached facts within --max-age, got err=%v calls=%d:\n%s", err, len(mock.Calls), out)
+	}
+
+	out, err = executeCommand(t, "facts")
+	if err != nil || len(mock.Calls) != 1 || !strings.Contains(out, "Linux 6.1.0-18-amd64") {
+		t.Errorf("Expected stale facts to be collected again, got err=%v calls=%d:\n%s", err, len(mock.Calls), out)
+	}
+}
+
+func TestInfoCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "web-1", Host: "10.0.0.1", User: "deploy"}})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			fmt.Fprint(stdout, factsOutput)
+			return nil
+		},
+	}
+	cmdRunner = mock
+
+	out, err := executeCommand(t, "info", "web-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "host: 10.0.0.1") || !strings.Contains(out, "No facts cached") || len(mock.Calls) != 0 {
+		t.Errorf("Expected server record without facts and no connection, got:\n%s", out)
+	}
+
+	out, err = executeCommand(t, "info", "web-1", "--refresh")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"Kernel:    Linux 6.1.0-18-amd64", "Uptime:    1h0m0s", "  /: 25% of 19.6 GiB used", "Addresses: 10.0.0.1"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	if _, err := executeCommand(t, "info", "web-9"); err == nil {
+		t.Error("Expected error for unknown server")
+	}
+}
+
+func TestServerPreview(t *testing.T) {
+	server := models.Server{Name: "db-1", Host: "10.0.1.1", User: "admin", Port: 2222, Tags: []string{"prod", "db"}, Jump: models.JumpChain{"bastion"}}
+
+	preview := serverPreview(server, map[string]string{})
+	for _, expected := range []string{"admin@10.0.1.1:2222", "Tags: prod, db", "Jump: bastion", "No cached facts"} {
+		if !strings.Contains(preview, expected) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", expected, preview)
+		}
+	}
+
+	preview = serverPreview(server, map[string]string{"db-1": "Kernel: Linux 6.1\n"})
+	if !strings.Contains(preview, "Kernel: Linux 6.1") {
+		t.Errorf("Expected cached facts in preview, got:\n%s", preview)
+	}
+}
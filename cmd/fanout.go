@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// forEachServer calls fn for every server, running at most concurrency calls
+// at a time. Servers not yet started when ctx is cancelled are skipped.
+func forEachServer(ctx context.Context, servers models.Servers, concurrency int, fn func(ctx context.Context, i int, s models.Server)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, s := range servers {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(i int, s models.Server) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(ctx, i, s)
+		}(i, s)
+	}
+	wg.Wait()
+}
+
+// prefixWriter writes complete lines to out, each prefixed with the given
+// label. Writers sharing a mutex never interleave within a line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+	// keep, if set, decides which lines are written; the others are dropped.
+	keep func(line []byte) bool
+}
+
+func newPrefixWriter(mu *sync.Mutex, out io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{mu: mu, out: out, prefix: prefix}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing partial line, if any.
+func (w *prefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	if w.keep != nil && !w.keep(bytes.TrimRight(line, "\r\n")) {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.out, w.prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(line)
+	return err
+}
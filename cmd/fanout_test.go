@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := newPrefixWriter(&mu, &out, "web | ")
+
+	w.Write([]byte("first "))
+	w.Write([]byte("line\nsecond line\npartial"))
+	if out.String() != "web | first line\nweb | second line\n" {
+		t.Errorf("Unexpected output before flush: %q", out.String())
+	}
+
+	w.Flush()
+	if out.String() != "web | first line\nweb | second line\nweb | partial\n" {
+		t.Errorf("Unexpected output after flush: %q", out.String())
+	}
+}
+
+func TestForEachServer_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	servers := models.Servers{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+
+	var mu sync.Mutex
+	var started []string
+	forEachServer(ctx, servers, 1, func(ctx context.Context, i int, s models.Server) {
+		mu.Lock()
+		started = append(started, s.Name)
+		mu.Unlock()
+		cancel()
+	})
+
+	if len(started) == len(servers) {
+		t.Errorf("Expected remaining servers to be skipped after cancel, got %v", started)
+	}
+}
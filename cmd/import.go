@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// importCandidate is a server read from another tool, with notes about what
+// could not be carried over.
+type importCandidate struct {
+	Server models.Server
+	Notes  []string
+}
+
+// importBatch is what an importer hands to runImport. With a SyncKey, the
+// private servers imported under the same key before are kept in sync:
+// they are updated without asking and removed once they are no longer in
+// Candidates.
+type importBatch struct {
+	Candidates []importCandidate
+	Notes      []string
+	SyncKey    string
+}
+
+type importAction int
+
+const (
+	importAdd importAction = iota
+	importUpdate
+	importUnchanged
+	importSkip
+	importRemove
+)
+
+type importPlanEntry struct {
+	Candidate importCandidate
+	Action    importAction
+	// Existing is the inventory entry the candidate collides with, if any.
+	Existing    *models.ServerWithSource
+	RenamedFrom string
+	Reason      string
+	Diff        []string
+}
+
+type importOptions struct {
+	Shared     bool
+	OnConflict string
+	Yes        bool
+	DryRun     bool
+	Tags       []string
+	// Owned maps the names a synced import wrote before, as they are in
+	// the source, to their names in the inventory.
+	Owned map[string]string
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import servers from other tools",
+	Long: `Import servers from other tools into sshy.
+
+Imported servers are added to the private servers in your local config, or to
+the shared servers file with --shared. A preview of the changes is shown
+first, including a diff for servers that already exist, and nothing is
+written until you confirm. --on-conflict decides what happens to names that
+already exist in the inventory: skip them (the default), overwrite them, or
+import them under a new name.`,
+}
+
+func importOptionsFromFlags(cmd *cobra.Command) (importOptions, error) {
+	var opts importOptions
+	opts.Shared, _ = cmd.Flags().GetBool("shared")
+	opts.OnConflict, _ = cmd.Flags().GetString("on-conflict")
+	opts.Yes, _ = cmd.Flags().GetBool("yes")
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.Tags, _ = cmd.Flags().GetStringSlice("tag")
+	switch opts.OnConflict {
+	case "skip", "overwrite", "rename":
+	default:
+		return opts, fmt.Errorf("invalid --on-conflict %q: use skip, overwrite or rename", opts.OnConflict)
+	}
+	return opts, nil
+}
+
+// runImport previews the batch against the inventory, asks for
+// confirmation and writes it. The batch notes are printed with the preview.
+func runImport(cmd *cobra.Command, batch importBatch) error {
+	opts, err := importOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	candidates := batch.Candidates
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	if opts.Shared && cfg.IsRemoteSource() {
+		return fmt.Errorf("shared servers are loaded from a URL and cannot be written; import without --shared")
+	}
+	existing, err := config.LoadServersWithSourceFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("error loading servers: %w", err)
+	}
+	if opts.Shared {
+		// Only the main servers file is written; servers of the additional
+		// sources cannot be updated there.
+		main := existing[:0]
+		for _, s := range existing {
+			if s.Origin == "" {
+				main = append(main, s)
+			}
+		}
+		existing = main
+	}
+	if batch.SyncKey != "" {
+		if opts.Shared {
+			return fmt.Errorf("--sync keeps private servers in sync and cannot be used with --shared")
+		}
+		localConfig, err := config.LoadLocalConfig()
+		if err != nil {
+			return fmt.Errorf("error loading local config: %w", err)
+		}
+		opts.Owned = make(map[string]string)
+		for name, inventoryName := range localConfig.Generated[batch.SyncKey] {
+			opts.Owned[name] = inventoryName
+		}
+	}
+
+	for i := range candidates {
+		candidates[i].Server.Tags = mergeTags(candidates[i].Server.Tags, opts.Tags)
+	}
+	plan := planImport(candidates, existing, opts)
+
+	destination := "local config"
+	if opts.Shared {
+		destination = "shared servers (" + cfg.ServersPath + ")"
+	}
+	fmt.Fprintf(out, "Import into %s:\n", destination)
+	printImportPlan(out, plan)
+	for _, note := range batch.Notes {
+		fmt.Fprintln(out, note)
+	}
+
+	counts := make(map[importAction]int)
+	for _, entry := range plan {
+		counts[entry.Action]++
+	}
+	fmt.Fprintf(out, "\n%d to add, %d to update, %d unchanged, %d skipped",
+		counts[importAdd], counts[importUpdate], counts[importUnchanged], counts[importSkip])
+	if batch.SyncKey != "" {
+		fmt.Fprintf(out, ", %d to remove", counts[importRemove])
+	}
+	fmt.Fprintln(out)
+
+	if counts[importAdd]+counts[importUpdate]+counts[importRemove] == 0 {
+		fmt.Fprintln(out, "Nothing to import")
+		return nil
+	}
+	if opts.DryRun {
+		return nil
+	}
+	if !opts.Yes && !promptConfirm(bufio.NewReader(cmd.InOrStdin()), "Apply these changes?") {
+		return fmt.Errorf("import cancelled")
+	}
+
+	if err := applyImport(cfg, plan, opts, batch.SyncKey); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Imported %d servers\n", counts[importAdd]+counts[importUpdate])
+	if counts[importRemove] > 0 {
+		fmt.Fprintf(out, "Removed %d servers\n", counts[importRemove])
+	}
+	return nil
+}
+
+// planImport decides what to do with every candidate. Collisions are
+// checked against the merged inventory and against earlier candidates.
+// New servers that log in to the same address as another one, under another
+// name, are skipped as duplicates. Servers in opts.Owned are matched under the
+// name they were written with, updated whatever --on-conflict says, and
+// removed when no candidate has their name.
+func planImport(candidates []importCandidate, existing []models.ServerWithSource, opts importOptions) []importPlanEntry {
+	byName := make(map[string]*models.ServerWithSource, len(existing))
+	for i := range existing {
+		byName[existing[i].Server.Name] = &existing[i]
+	}
+	taken := make(map[string]bool, len(existing)+len(candidates))
+	for name := range byName {
+		taken[name] = true
+	}
+	planned := make(map[string]bool, len(candidates))
+
+	// Servers an earlier sync created whose source entries are gone are
+	// removed, so they must not block a renamed entry at the same address.
+	inSource := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		inSource[c.Server.Name] = true
+	}
+	removing := make(map[string]bool)
+	for name, target := range opts.Owned {
+		if s, ok := byName[target]; ok && s.Source != models.SourceShared && !inSource[name] {
+			removing[target] = true
+		}
+	}
+	addresses := make(map[string]string, len(existing)+len(candidates))
+	for _, s := range existing {
+		if !removing[s.Server.Name] {
+			addresses[serverAddress(s.Server)] = s.Server.Name
+		}
+	}
+
+	plan := make([]importPlanEntry, 0, len(candidates))
+	for _, c := range candidates {
+		entry := importPlanEntry{Candidate: c, Action: importAdd}
+		name := c.Server.Name
+		current, exists := byName[name]
+		owned := false
+		if target, ok := opts.Owned[name]; ok && name != "" {
+			if s, found := byName[target]; !found || s.Source != models.SourceShared {
+				current, exists, owned = s, found, true
+				if target != name {
+					entry.RenamedFrom = name
+					entry.Candidate.Server.Name = target
+				}
+			}
+		}
+
+		switch {
+		case name == "":
+			entry.Action, entry.Reason = importSkip, "no name"
+		case planned[name]:
+			entry.Action, entry.Reason = importSkip, "duplicate name in the source"
+		case !exists:
+		case sameServer(current.Server, entry.Candidate.Server):
+			entry.Action, entry.Existing = importUnchanged, current
+		case owned:
+			entry.Action, entry.Existing = importUpdate, current
+			entry.Diff = serverDiff(current.Server, entry.Candidate.Server)
+		case opts.OnConflict == "rename":
+			entry.RenamedFrom = name
+			entry.Candidate.Server.Name = uniqueName(name, taken)
+		case opts.OnConflict == "overwrite" && opts.Shared && current.Source == models.SourceLocal:
+			entry.Action, entry.Reason = importSkip, "exists as a private server"
+		case opts.OnConflict == "overwrite":
+			entry.Action, entry.Existing = importUpdate, current
+			entry.Diff = serverDiff(current.Server, c.Server)
+		default:
+			entry.Action, entry.Reason = importSkip, "already exists"
+		}
+
+		if entry.Action == importUpdate && !opts.Shared && current.Source != models.SourceLocal {
+			entry = overrideEntry(entry, current.Server)
+		}
+		if other, dup := addresses[serverAddress(entry.Candidate.Server)]; dup && entry.Action == importAdd {
+			entry.Action, entry.Reason = importSkip, "same address as "+other
+		}
+		if entry.Action == importAdd || entry.Action == importUpdate {
+			taken[entry.Candidate.Server.Name] = true
+			addresses[serverAddress(entry.Candidate.Server)] = entry.Candidate.Server.Name
+		}
+		if name != "" {
+			planned[name] = true
+		}
+		plan = append(plan, entry)
+	}
+
+	for i, s := range existing {
+		if removing[s.Server.Name] {
+			plan = append(plan, importPlanEntry{
+				Candidate: importCandidate{Server: s.Server},
+				Action:    importRemove,
+				Existing:  &existing[i],
+				Reason:    "no longer in the source",
+			})
+		}
+	}
+	return plan
+}
+
+// overrideEntry plans an update of a shared server, which applyImport writes
+// as a local override. An override only sets fields, so the update is
+// compared with the merged result and the fields it cannot clear are noted.
+func overrideEntry(entry importPlanEntry, current models.Server) importPlanEntry {
+	merged := config.ApplyOverride(current, entry.Candidate.Server)
+	entry.Diff = serverDiff(current, merged)
+	if len(entry.Diff) == 0 {
+		entry.Action, entry.Diff = importUnchanged, nil
+		return entry
+	}
+	wanted := serverFields(entry.Candidate.Server)
+	var kept []string
+	for field := range serverFields(merged) {
+		if _, ok := wanted[field]; !ok {
+			kept = append(kept, field)
+		}
+	}
+	if len(kept) > 0 {
+		sort.Strings(kept)
+		entry.Candidate.Notes = append(entry.Candidate.Notes,
+			fmt.Sprintf("%s kept: a local override cannot clear fields of a shared server", strings.Join(kept, ", ")))
+	}
+	return entry
+}
+
+func printImportPlan(w io.Writer, plan []importPlanEntry) {
+	for _, entry := range plan {
+		s := entry.Candidate.Server
+		address := s.Host
+		if s.User != "" {
+			address = s.User + "@" + address
+		}
+		if s.Port != 0 && s.Port != 22 {
+			address = fmt.Sprintf("%s:%d", address, s.Port)
+		}
+
+		switch entry.Action {
+		case importAdd:
+			if entry.RenamedFrom != "" {
+				fmt.Fprintf(w, "  + %s  %s  (renamed from %s)\n", s.Name, address, entry.RenamedFrom)
+			} else {
+				fmt.Fprintf(w, "  + %s  %s\n", s.Name, address)
+			}
+		case importUpdate:
+			fmt.Fprintf(w, "  ~ %s  %s\n", s.Name, address)
+			for _, line := range entry.Diff {
+				fmt.Fprintf(w, "      %s\n", line)
+			}
+		case importUnchanged:
+			fmt.Fprintf(w, "  = %s  (unchanged)\n", s.Name)
+		case importSkip:
+			fmt.Fprintf(w, "  ! %s  (skipped: %s)\n", s.Name, entry.Reason)
+		case importRemove:
+			fmt.Fprintf(w, "  - %s  (removed: %s)\n", s.Name, entry.Reason)
+		}
+		if entry.Action == importAdd || entry.Action == importUpdate {
+			for _, note := range entry.Candidate.Notes {
+				fmt.Fprintf(w, "      note: %s\n", note)
+			}
+		}
+	}
+}
+
+func applyImport(cfg *config.GlobalConfig, plan []importPlanEntry, opts importOptions, syncKey string) error {
+	if opts.Shared {
+		servers, err := config.LoadSharedServers(cfg.ConfigPath, cfg.ServersPath)
+		if err != nil {
+			return fmt.Errorf("error loading shared servers: %w", err)
+		}
+		for _, entry := range plan {
+			servers = upsertServer(servers, entry)
+		}
+		if err := config.SaveServersWithPath(cfg.ConfigPath, cfg.ServersPath, servers); err != nil {
+			return fmt.Errorf("error saving shared servers: %w", err)
+		}
+		return nil
+	}
+
+	localConfig, err := config.LoadLocalConfig()
+	if err != nil {
+		return fmt.Errorf("error loading local config: %w", err)
+	}
+	removed := make(map[string]bool)
+	generated := make(map[string]string)
+	for _, entry := range plan {
+		name := entry.Candidate.Server.Name
+		sourceName := name
+		if entry.RenamedFrom != "" {
+			sourceName = entry.RenamedFrom
+		}
+		_, owned := opts.Owned[sourceName]
+		switch {
+		case entry.Action == importRemove && entry.Existing.Source == models.SourceOverride:
+			// The shared server stays; only what the import wrote goes.
+			delete(localConfig.Servers, name)
+			continue
+		case entry.Action == importRemove:
+			removed[name] = true
+			continue
+		case entry.Action == importAdd, entry.Action == importUpdate,
+			entry.Action == importUnchanged && owned:
+			generated[sourceName] = name
+		}
+		if entry.Action == importUpdate && entry.Existing.Source != models.SourceLocal {
+			// Shared servers are overridden locally rather than edited.
+			if localConfig.Servers == nil {
+				localConfig.Servers = make(map[string]models.Server)
+			}
+			// Fields the import leaves empty keep what the override had,
+			// as the plan showed.
+			override := config.ApplyOverride(localConfig.Servers[name], entry.Candidate.Server)
+			override.Name = name
+			localConfig.Servers[name] = override
+			continue
+		}
+		localConfig.Private = upsertServer(localConfig.Private, entry)
+	}
+
+	if len(removed) > 0 {
+		kept := localConfig.Private[:0]
+		for _, s := range localConfig.Private {
+			if !removed[s.Name] {
+				kept = append(kept, s)
+			}
+		}
+		localConfig.Private = kept
+	}
+	if syncKey != "" {
+		if localConfig.Generated == nil {
+			localConfig.Generated = make(map[string]map[string]string)
+		}
+		localConfig.Generated[syncKey] = generated
+	}
+	if err := config.SaveLocalConfig(localConfig); err != nil {
+		return fmt.Errorf("error saving local config: %w", err)
+	}
+	return nil
+}
+
+// addPrivateServers adds servers picked by the user to the local config,
+// renaming those whose names are taken, and lists what was added.
+func addPrivateServers(out io.Writer, cfg *config.GlobalConfig, existing []models.ServerWithSource, servers models.Servers) error {
+	candidates := make([]importCandidate, len(servers))
+	for i, s := range servers {
+		candidates[i] = importCandidate{Server: s}
+	}
+	opts := importOptions{OnConflict: "rename"}
+	plan := planImport(candidates, existing, opts)
+	if err := applyImport(cfg, plan, opts, ""); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Added to local config:")
+	printImportPlan(out, plan)
+	return nil
+}
+
+// upsertServer applies an add or update plan entry to servers.
+func upsertServer(servers models.Servers, entry importPlanEntry) models.Servers {
+	switch entry.Action {
+	case importAdd:
+		return append(servers, entry.Candidate.Server)
+	case importUpdate:
+		for i, s := range servers {
+			if s.Name == entry.Candidate.Server.Name {
+				servers[i] = entry.Candidate.Server
+				return servers
+			}
+		}
+		return append(servers, entry.Candidate.Server)
+	}
+	return servers
+}
+
+// serverAddress identifies where a server logs in, to find the same server
+// imported under another name.
+func serverAddress(s models.Server) string {
+	port := s.Port
+	if port == 0 {
+		port = 22
+	}
+	return fmt.Sprintf("%s@%s:%d via %s", s.User, s.Host, port, strings.Join(s.JumpHops(), ","))
+}
+
+func uniqueName(name string, taken map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+func mergeTags(tags, extra []string) []string {
+	for _, tag := range extra {
+		if !hasAllTags(tags, []string{tag}) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func sameServer(a, b models.Server) bool {
+	return len(serverDiff(a, b)) == 0
+}
+
+// serverDiff lists the fields that differ between two servers, as
+// "field: old -> new".
+func serverDiff(old, new models.Server) []string {
+	oldFields, newFields := serverFields(old), serverFields(new)
+	keys := make([]string, 0, len(oldFields)+len(newFields))
+	for k := range oldFields {
+		keys = append(keys, k)
+	}
+	for k := range newFields {
+		if _, ok := oldFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var diff []string
+	for _, k := range keys {
+		o, n := oldFields[k], newFields[k]
+		if o != n {
+			diff = append(diff, fmt.Sprintf("%s: %s -> %s", k, orDash(o), orDash(n)))
+		}
+	}
+	return diff
+}
+
+// serverFields renders each set field of a server as compact JSON, keyed by
+// its YAML name, so that values read from different formats compare equal.
+func serverFields(s models.Server) map[string]string {
+	data, _ := yaml.Marshal(s)
+	var raw map[string]interface{}
+	yaml.Unmarshal(data, &raw)
+	fields := make(map[string]string, len(raw))
+	for k, v := range raw {
+		encoded, _ := json.Marshal(v)
+		fields[k] = string(encoded)
+	}
+	return fields
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.PersistentFlags().Bool("shared", false, "Import into the shared servers file instead of the local config")
+	importCmd.PersistentFlags().String("on-conflict", "skip", "What to do with names that already exist: skip, overwrite or rename")
+	importCmd.PersistentFlags().BoolP("yes", "y", false, "Apply the changes without asking")
+	importCmd.PersistentFlags().Bool("dry-run", false, "Only show what would be imported")
+	importCmd.PersistentFlags().StringSlice("tag", []string{}, "Add these tags to every imported server")
+}
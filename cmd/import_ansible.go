@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/inventory"
+	"github.com/spf13/cobra"
+)
+
+var importAnsibleCmd = &cobra.Command{
+	Use:   "ansible <inventory>",
+	Short: "Import servers from an Ansible inventory",
+	Long: `Import servers from an Ansible inventory in INI or YAML format.
+
+Host ranges such as web[01:03].example.com are expanded. Every group a host
+belongs to, directly or through group children, becomes a tag. Connection
+variables (ansible_host, ansible_user, ansible_port,
+ansible_ssh_private_key_file and the -o, -J and -i arguments of
+ansible_ssh_common_args) are taken from the host and its groups; other
+variables are not imported.`,
+	Example: `  sshy import ansible inventory/hosts.ini --dry-run
+  sshy import ansible inventory/prod.yml --tag prod`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		if format == "auto" {
+			format = ansibleFormat(args[0])
+		}
+
+		var hosts []inventory.Host
+		switch format {
+		case "ini":
+			hosts, err = inventory.ParseAnsibleINI(bytes.NewReader(data))
+		case "yaml":
+			hosts, err = inventory.ParseAnsibleYAML(data)
+		default:
+			return fmt.Errorf("unknown format %q: use auto, ini or yaml", format)
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", args[0], err)
+		}
+		return runImport(cmd, importBatch{Candidates: importCandidates(hosts)})
+	},
+}
+
+func ansibleFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml", ".json":
+		return "yaml"
+	}
+	return "ini"
+}
+
+func importCandidates(hosts []inventory.Host) []importCandidate {
+	candidates := make([]importCandidate, len(hosts))
+	for i, h := range hosts {
+		candidates[i] = importCandidate{Server: h.Server, Notes: h.Notes}
+	}
+	return candidates
+}
+
+func init() {
+	importCmd.AddCommand(importAnsibleCmd)
+
+	importAnsibleCmd.Flags().String("format", "auto", "Inventory format: auto, ini or yaml (auto goes by the file extension)")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/omisai-tech/sshy/internal/inventory"
+	"github.com/spf13/cobra"
+)
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv <file>",
+	Short: "Import servers from a CSV file",
+	Long: `Import servers from a CSV file with a header row. Use - to read from stdin,
+together with --yes or --dry-run since stdin cannot also answer the prompt.
+
+The columns are named after the server fields: name, host, user, port, key,
+tags and jump. Use --column to map a field to a different header. Tags and
+jump hosts are separated by --delimiter inside a cell. Columns named
+option.<Keyword>, such as option.ForwardAgent, set SSH options; other
+columns are ignored. Invalid rows are reported by row number and nothing is
+imported until they are fixed.`,
+	Example: `  sshy import csv hosts.csv --dry-run
+  sshy import csv hosts.csv --column name=Hostname --column host="IP address" --column tags=Groups --delimiter "|"`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := csvOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		var r io.Reader = cmd.InOrStdin()
+		if args[0] == "-" {
+			yes, _ := cmd.Flags().GetBool("yes")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if !yes && !dryRun {
+				return fmt.Errorf("reading the CSV from stdin needs --yes or --dry-run, as the confirmation is read from stdin too")
+			}
+		} else {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		hosts, err := inventory.ParseCSV(r, opts)
+		if err != nil {
+			return fmt.Errorf("error reading %s:\n%w", args[0], err)
+		}
+		return runImport(cmd, importBatch{Candidates: importCandidates(hosts)})
+	},
+}
+
+func csvOptionsFromFlags(cmd *cobra.Command) (inventory.CSVOptions, error) {
+	specs, _ := cmd.Flags().GetStringArray("column")
+	delimiter, _ := cmd.Flags().GetString("delimiter")
+	columns, err := inventory.ParseCSVColumns(specs)
+	if err != nil {
+		return inventory.CSVOptions{}, err
+	}
+	return inventory.CSVOptions{Columns: columns, Delimiter: delimiter}, nil
+}
+
+func addCSVFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("column", []string{}, "Map a server field to a column header, as field=Header (repeatable)")
+	cmd.Flags().String("delimiter", ";", "Separator for tags, jump hosts and repeated option values inside a cell")
+}
+
+func init() {
+	importCmd.AddCommand(importCSVCmd)
+	addCSVFlags(importCSVCmd)
+}
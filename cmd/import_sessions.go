@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/omisai-tech/sshy/internal/inventory"
+	"github.com/spf13/cobra"
+)
+
+var importPuttyCmd = &cobra.Command{
+	Use:   "putty [path]",
+	Short: "Import PuTTY sessions",
+	Long: `Import SSH sessions saved by PuTTY on Linux, from ~/.putty/sessions or the
+given directory or session file.
+
+Host, port, user, key file, agent forwarding, compression, port forwardings
+and SSH proxies (as jump hosts) are imported. Keys in PuTTY's .ppk format
+must be converted with puttygen first; they and other settings that cannot
+be carried over are listed in the preview.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := importPath(args, ".putty", "sessions")
+		if err != nil {
+			return err
+		}
+		hosts, skipped, err := inventory.ParsePuttySessions(path)
+		if err != nil {
+			return fmt.Errorf("error reading PuTTY sessions: %w", err)
+		}
+		return runImport(cmd, newImportBatch(hosts, skipped))
+	},
+}
+
+var importRemminaCmd = &cobra.Command{
+	Use:   "remmina [path]",
+	Short: "Import Remmina SSH connections",
+	Long: `Import SSH connections saved by Remmina, from ~/.local/share/remmina or the
+given directory or .remmina file. Connections using other protocols are
+skipped.
+
+Server, port, user, private key, group (as a tag) and the SSH tunnel (as a
+jump host) are imported. Passwords are never imported; they and other
+settings that cannot be carried over are listed in the preview.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := importPath(args, ".local", "share", "remmina")
+		if err != nil {
+			return err
+		}
+		hosts, skipped, err := inventory.ParseRemminaFiles(path)
+		if err != nil {
+			return fmt.Errorf("error reading Remmina connections: %w", err)
+		}
+		return runImport(cmd, newImportBatch(hosts, skipped))
+	},
+}
+
+var importJSONCmd = &cobra.Command{
+	Use:     "json <file>",
+	Aliases: []string{"termius"},
+	Short:   "Import hosts from a Termius or other JSON export",
+	Long: `Import hosts from a JSON export: a list of host objects, or an object with
+such a list under "hosts", as written by Termius and similar tools.
+
+The usual attribute names are recognised: label or name, address or host,
+port, username, identity_file or key, jump or proxy_jump, and tags, groups
+or group (as tags). Keys kept in an app's vault and other attributes that
+cannot be carried over are listed in the preview.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		hosts, skipped, err := inventory.ParseJSONHosts(data)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", args[0], err)
+		}
+		return runImport(cmd, newImportBatch(hosts, skipped))
+	},
+}
+
+// importPath returns the path argument, or the default location under the
+// home directory.
+func importPath(args []string, defaultPath ...string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{home}, defaultPath...)...), nil
+}
+
+// newImportBatch lists the reasons for skipped entries as batch notes.
+func newImportBatch(hosts []inventory.Host, skipped []string) importBatch {
+	batch := importBatch{Candidates: importCandidates(hosts)}
+	for _, reason := range skipped {
+		batch.Notes = append(batch.Notes, "Skipped "+reason)
+	}
+	return batch
+}
+
+func init() {
+	importCmd.AddCommand(importPuttyCmd)
+	importCmd.AddCommand(importRemminaCmd)
+	importCmd.AddCommand(importJSONCmd)
+}
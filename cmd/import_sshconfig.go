@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/sshconfig"
+	"github.com/spf13/cobra"
+)
+
+var importSSHConfigCmd = &cobra.Command{
+	Use:   "ssh-config [path]",
+	Short: "Import servers from an OpenSSH client config",
+	Long: `Import servers from an OpenSSH client config, ~/.ssh/config by default.
+
+Include directives are followed. Every concrete name of a Host line becomes a
+server; HostName, User, Port, IdentityFile and ProxyJump map to the matching
+server fields and other keywords are kept as options. Host blocks made only of
+wildcard patterns, and Match blocks, are skipped.`,
+	Example: `  sshy import ssh-config --dry-run
+  sshy import ssh-config ~/.ssh/config.d/work --tag work --on-conflict rename`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ""
+		if len(args) == 1 {
+			path = args[0]
+		} else {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(home, ".ssh", "config")
+		}
+
+		blocks, err := sshconfig.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading ssh config: %w", err)
+		}
+		imported, skipped := sshconfig.ToServers(blocks)
+
+		candidates := make([]importCandidate, len(imported))
+		for i, im := range imported {
+			candidates[i] = importCandidate{Server: im.Server, Notes: im.Notes}
+		}
+		var notes []string
+		if len(skipped) > 0 {
+			patterns := make([]string, len(skipped))
+			for i, block := range skipped {
+				patterns[i] = strings.Join(block.Patterns, " ")
+			}
+			notes = append(notes, fmt.Sprintf("Skipped %d wildcard Host blocks: %s", len(skipped), strings.Join(patterns, ", ")))
+		}
+		return runImport(cmd, importBatch{Candidates: candidates, Notes: notes})
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importSSHConfigCmd)
+}
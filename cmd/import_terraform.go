@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/inventory"
+	"github.com/spf13/cobra"
+)
+
+var importTerraformCmd = &cobra.Command{
+	Use:   "terraform <terraform.tfstate>",
+	Short: "Import instances from a Terraform state file",
+	Long: `Import instances from a local Terraform state file (format version 4).
+
+Supported resource types: ` + strings.Join(inventory.TerraformTypes(), ", ") + `.
+By default the server is named after the instance (its Name tag on AWS) and
+uses its public address, falling back to the private one. A mapping file
+chooses the name, address, user, port, key and tags from resource
+attributes:
+
+  defaults:
+    host: private
+    user: ubuntu
+    tags: ["terraform", "{tags.Role}"]
+  types:
+    google_compute_instance:
+      name: "{labels.hostname|name}"
+      tags: ["{labels.env}"]
+
+{path} refers to an attribute, with "|" between fallbacks; host may also be
+"public" or "private".
+
+With --sync, the servers imported from the same state file before are kept
+in sync: they are updated without asking and removed once their resources
+are gone.`,
+	Example: `  sshy import terraform terraform.tfstate --dry-run
+  sshy import terraform infra/terraform.tfstate --mapping sshy-mapping.yaml --sync --yes`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mappingPath, _ := cmd.Flags().GetString("mapping")
+		sync, _ := cmd.Flags().GetBool("sync")
+
+		var mapping inventory.TerraformMapping
+		if mappingPath != "" {
+			var err error
+			if mapping, err = inventory.LoadTerraformMapping(mappingPath); err != nil {
+				return fmt.Errorf("error loading mapping: %w", err)
+			}
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		instances, err := inventory.ParseTerraformState(data)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", args[0], err)
+		}
+		hosts, skipped := inventory.TerraformHosts(instances, mapping)
+
+		batch := newImportBatch(hosts, skipped)
+		if sync {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			batch.SyncKey = "terraform:" + path
+		}
+		return runImport(cmd, batch)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importTerraformCmd)
+
+	importTerraformCmd.Flags().String("mapping", "", "YAML file choosing name, host, user, port, key and tags from resource attributes")
+	importTerraformCmd.Flags().Bool("sync", false, "Update and remove the servers imported from this state file before")
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func writeState(t *testing.T, path string, instances map[string]string) {
+	t.Helper()
+	var parts []string
+	for name, ip := range instances {
+		parts = append(parts, fmt.Sprintf(`{"attributes": {"name": %q, "ipv4_address": %q}}`, name, ip))
+	}
+	state := fmt.Sprintf(`{"version": 4, "resources": [{"mode": "managed", "type": "hcloud_server", "name": "nodes", "instances": [%s]}]}`,
+		strings.Join(parts, ","))
+	if err := os.WriteFile(path, []byte(state), 0644); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+}
+
+func TestImportTerraformCmd_Sync(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	local := config.LocalConfig{Private: models.Servers{{Name: "manual", Host: "10.9.9.9"}}}
+	if err := config.SaveLocalConfig(local); err != nil {
+		t.Fatalf("Failed to save local config: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+
+	writeState(t, path, map[string]string{"node-1": "10.0.0.1", "node-2": "10.0.0.2", "manual": "10.0.0.3"})
+	if _, err := executeCommand(t, "import", "terraform", path, "--sync", "--yes"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	writeState(t, path, map[string]string{"node-1": "10.0.0.10", "manual": "10.0.0.3"})
+	output, err := executeCommand(t, "import", "terraform", path, "--sync", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		`host: "10.0.0.1" -> "10.0.0.10"`,
+		"- node-2  (removed: no longer in the source)",
+		"! manual  (skipped: already exists)",
+		"0 to add, 1 to update, 0 unchanged, 1 skipped, 1 to remove",
+		"Removed 1 servers",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	local, _ = config.LoadLocalConfig()
+	var names []string
+	for _, s := range local.Private {
+		names = append(names, s.Name+"="+s.Host)
+	}
+	if strings.Join(names, ",") != "manual=10.9.9.9,node-1=10.0.0.10" {
+		t.Errorf("Expected the manual server and node-1, got %v", names)
+	}
+	absPath, _ := filepath.Abs(path)
+	if generated := local.Generated["terraform:"+absPath]; len(generated) != 1 || generated["node-1"] != "node-1" {
+		t.Errorf("Expected node-1 to be recorded as generated, got %v", local.Generated)
+	}
+}
+
+func TestImportTerraformCmd_SyncRename(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "web-3", Host: "10.9.9.3"}})
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	writeState(t, path, map[string]string{"web-3": "10.0.0.3"})
+
+	for run := 1; run <= 3; run++ {
+		output, err := executeCommand(t, "import", "terraform", path, "--sync", "--on-conflict", "rename", "--yes")
+		if err != nil {
+			t.Fatalf("Run %d: expected no error, got %v", run, err)
+		}
+		want := "= web-3-2  (unchanged)"
+		if run == 1 {
+			want = "+ web-3-2  10.0.0.3  (renamed from web-3)"
+		}
+		if !strings.Contains(output, want) || !strings.Contains(output, "0 to remove") {
+			t.Errorf("Run %d: expected output to contain %q and remove nothing, got:\n%s", run, want, output)
+		}
+	}
+
+	local, _ := config.LoadLocalConfig()
+	if len(local.Private) != 1 || local.Private[0].Name != "web-3-2" || local.Private[0].Host != "10.0.0.3" {
+		t.Errorf("Expected only web-3-2, got %+v", local.Private)
+	}
+	absPath, _ := filepath.Abs(path)
+	if generated := local.Generated["terraform:"+absPath]; generated["web-3"] != "web-3-2" {
+		t.Errorf("Expected web-3 to be recorded as web-3-2, got %v", local.Generated)
+	}
+
+	writeState(t, path, map[string]string{})
+	output, err := executeCommand(t, "import", "terraform", path, "--sync", "--on-conflict", "rename", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "- web-3-2  (removed: no longer in the source)") {
+		t.Errorf("Expected web-3-2 to be removed, got:\n%s", output)
+	}
+}
+
+func TestImportTerraformCmd_SyncRenamedInSource(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+
+	writeState(t, path, map[string]string{"old-name": "10.0.0.1"})
+	if _, err := executeCommand(t, "import", "terraform", path, "--sync", "--yes"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	writeState(t, path, map[string]string{"new-name": "10.0.0.1"})
+	output, err := executeCommand(t, "import", "terraform", path, "--sync", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "+ new-name  10.0.0.1") || !strings.Contains(output, "- old-name  (removed: no longer in the source)") {
+		t.Errorf("Expected new-name to replace old-name, got:\n%s", output)
+	}
+	local, _ := config.LoadLocalConfig()
+	if len(local.Private) != 1 || local.Private[0].Name != "new-name" || local.Private[0].Host != "10.0.0.1" {
+		t.Errorf("Expected only new-name, got %+v", local.Private)
+	}
+}
+
+func TestImportTerraformCmd_OverwriteShared(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "node-1", Host: "10.9.9.1", Key: "~/.ssh/node"}})
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	writeState(t, path, map[string]string{"node-1": "10.0.0.1"})
+
+	output, err := executeCommand(t, "import", "terraform", path, "--on-conflict", "overwrite", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, `host: "10.9.9.1" -> "10.0.0.1"`) || strings.Contains(output, "key:") {
+		t.Errorf("Expected only the host to change, got:\n%s", output)
+	}
+	if !strings.Contains(output, "note: key kept: a local override cannot clear fields of a shared server") {
+		t.Errorf("Expected a note that the key is kept, got:\n%s", output)
+	}
+
+	output, err = executeCommand(t, "import", "terraform", path, "--on-conflict", "overwrite", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "= node-1  (unchanged)") {
+		t.Errorf("Expected the second import to change nothing, got:\n%s", output)
+	}
+}
+
+func TestImportTerraformCmd_SyncRemovesOverride(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "node-1", Host: "10.9.9.1"}})
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+
+	writeState(t, path, map[string]string{"node-1": "10.0.0.1"})
+	if _, err := executeCommand(t, "import", "terraform", path, "--sync", "--on-conflict", "overwrite", "--yes"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	local, _ := config.LoadLocalConfig()
+	if local.Servers["node-1"].Host != "10.0.0.1" {
+		t.Fatalf("Expected node-1 to be overridden, got %+v", local.Servers)
+	}
+
+	writeState(t, path, map[string]string{})
+	output, err := executeCommand(t, "import", "terraform", path, "--sync", "--on-conflict", "overwrite", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "- node-1  (removed: no longer in the source)") {
+		t.Errorf("Expected the override to be removed, got:\n%s", output)
+	}
+	local, _ = config.LoadLocalConfig()
+	if _, ok := local.Servers["node-1"]; ok {
+		t.Errorf("Expected the node-1 override to be gone, got %+v", local.Servers)
+	}
+}
+
+func TestImportTerraformCmd_SyncShared(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	writeState(t, path, map[string]string{"node-1": "10.0.0.1"})
+
+	_, err := executeCommand(t, "import", "terraform", path, "--sync", "--shared", "--yes")
+	if err == nil || !strings.Contains(err.Error(), "cannot be used with --shared") {
+		t.Errorf("Expected an error for --sync with --shared, got %v", err)
+	}
+}
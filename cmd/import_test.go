@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+func writeSSHConfig(t *testing.T, content string) string {
+	t.Helper()
+	home, _ := os.UserHomeDir()
+	path := filepath.Join(home, ".ssh", "config")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("Failed to create .ssh dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write ssh config: %v", err)
+	}
+	return path
+}
+
+const importSSHConfig = `Host *
+  ServerAliveInterval 30
+
+Host web-1 web-2
+  HostName %h.example.com
+  User deploy
+
+Host db-1
+  HostName 10.0.0.9
+  User admin
+  Port 2222
+`
+
+func TestPlanImport(t *testing.T) {
+	existing := []models.ServerWithSource{
+		{Server: models.Server{Name: "web-1", Host: "web-1.example.com", User: "deploy"}, Source: models.SourceShared},
+		{Server: models.Server{Name: "db-1", Host: "10.0.0.1", User: "admin"}, Source: models.SourceShared},
+		{Server: models.Server{Name: "db-1-2", Host: "10.0.0.2"}, Source: models.SourceLocal},
+		{Server: models.Server{Name: "mine", Host: "10.0.0.3"}, Source: models.SourceLocal},
+	}
+	candidates := []importCandidate{
+		{Server: models.Server{Name: "web-1", Host: "web-1.example.com", User: "deploy"}},
+		{Server: models.Server{Name: "db-1", Host: "10.0.0.9", User: "admin"}},
+		{Server: models.Server{Name: "mine", Host: "10.0.0.4"}},
+		{Server: models.Server{Name: "new", Host: "10.0.0.5"}},
+		{Server: models.Server{Name: "new", Host: "10.0.0.6"}},
+	}
+
+	tests := []struct {
+		name     string
+		opts     importOptions
+		expected []importAction
+		names    []string
+	}{
+		{
+			name:     "skip",
+			opts:     importOptions{OnConflict: "skip"},
+			expected: []importAction{importUnchanged, importSkip, importSkip, importAdd, importSkip},
+			names:    []string{"web-1", "db-1", "mine", "new", "new"},
+		},
+		{
+			name:     "overwrite",
+			opts:     importOptions{OnConflict: "overwrite"},
+			expected: []importAction{importUnchanged, importUpdate, importUpdate, importAdd, importSkip},
+			names:    []string{"web-1", "db-1", "mine", "new", "new"},
+		},
+		{
+			name:     "overwrite private into shared",
+			opts:     importOptions{OnConflict: "overwrite", Shared: true},
+			expected: []importAction{importUnchanged, importUpdate, importSkip, importAdd, importSkip},
+			names:    []string{"web-1", "db-1", "mine", "new", "new"},
+		},
+		{
+			name:     "rename",
+			opts:     importOptions{OnConflict: "rename"},
+			expected: []importAction{importUnchanged, importAdd, importAdd, importAdd, importSkip},
+			names:    []string{"web-1", "db-1-3", "mine-2", "new", "new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planImport(candidates, existing, tt.opts)
+			for i, entry := range plan {
+				if entry.Action != tt.expected[i] {
+					t.Errorf("Expected action %d for %s, got %d", tt.expected[i], candidates[i].Server.Name, entry.Action)
+				}
+				if entry.Candidate.Server.Name != tt.names[i] {
+					t.Errorf("Expected name %q, got %q", tt.names[i], entry.Candidate.Server.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanImport_SameAddress(t *testing.T) {
+	existing := []models.ServerWithSource{
+		{Server: models.Server{Name: "web", Host: "10.0.0.1", User: "deploy"}, Source: models.SourceShared},
+	}
+	candidates := []importCandidate{
+		{Server: models.Server{Name: "prod-web", Host: "10.0.0.1", User: "deploy"}},
+		{Server: models.Server{Name: "web-root", Host: "10.0.0.1", User: "root"}},
+		{Server: models.Server{Name: "web-copy", Host: "10.0.0.1", User: "deploy", Jump: models.JumpChain{"bastion"}}},
+	}
+
+	plan := planImport(candidates, existing, importOptions{OnConflict: "skip"})
+	if plan[0].Action != importSkip || plan[0].Reason != "same address as web" {
+		t.Errorf("Expected prod-web to be skipped as a duplicate of web, got %d %q", plan[0].Action, plan[0].Reason)
+	}
+	if plan[1].Action != importAdd || plan[2].Action != importAdd {
+		t.Errorf("Expected other users and routes to be added, got %d and %d", plan[1].Action, plan[2].Action)
+	}
+}
+
+func TestImportPuttyCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web", Host: "web.example.com", User: "deploy"},
+	})
+	dir := t.TempDir()
+	sessions := map[string]string{
+		"prod%20web": "HostName=deploy@web.example.com\nProtocol=ssh\n",
+		"db":         "HostName=db.example.com\nUserName=admin\nProtocol=ssh\nPublicKeyFile=/keys/db.ppk\n",
+		"router":     "HostName=192.168.1.1\nProtocol=telnet\n",
+	}
+	for name, content := range sessions {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write session: %v", err)
+		}
+	}
+
+	output, err := executeCommand(t, "import", "putty", dir, "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"+ db  admin@db.example.com",
+		"note: key /keys/db.ppk is in PuTTY format",
+		"! prod web  (skipped: same address as web)",
+		"Skipped router: protocol telnet",
+		"Imported 1 servers",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	local, err := config.LoadLocalConfig()
+	if err != nil {
+		t.Fatalf("Failed to load local config: %v", err)
+	}
+	if len(local.Private) != 1 || local.Private[0].Name != "db" {
+		t.Errorf("Expected db to be imported as a private server, got %v", local.Private)
+	}
+}
+
+func TestServerDiff(t *testing.T) {
+	old := models.Server{Name: "db", Host: "10.0.0.1", User: "admin", Tags: []string{"db"}}
+	new := models.Server{Name: "db", Host: "10.0.0.9", User: "admin", Port: 2222, Tags: []string{"db"}}
+
+	diff := serverDiff(old, new)
+	expected := []string{`host: "10.0.0.1" -> "10.0.0.9"`, "port: - -> 2222"}
+	if strings.Join(diff, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected diff %v, got %v", expected, diff)
+	}
+	if !sameServer(old, old) {
+		t.Errorf("Expected a server to equal itself")
+	}
+}
+
+func TestImportSSHConfigCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "db-1", Host: "10.0.0.1", User: "admin"},
+	})
+	writeSSHConfig(t, importSSHConfig)
+
+	output, err := executeCommand(t, "import", "ssh-config", "--yes", "--tag", "imported")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"+ web-1  deploy@web-1.example.com",
+		"+ web-2  deploy@web-2.example.com",
+		"! db-1  (skipped: already exists)",
+		"Skipped 1 wildcard Host blocks: *",
+		"2 to add, 0 to update, 0 unchanged, 1 skipped",
+		"Imported 2 servers",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	local, err := config.LoadLocalConfig()
+	if err != nil {
+		t.Fatalf("Failed to load local config: %v", err)
+	}
+	if len(local.Private) != 2 {
+		t.Fatalf("Expected 2 private servers, got %d", len(local.Private))
+	}
+	if local.Private[0].Name != "web-1" || local.Private[0].Host != "web-1.example.com" {
+		t.Errorf("Expected web-1 at web-1.example.com, got %+v", local.Private[0])
+	}
+	if !contains(local.Private[0].Tags, "imported") {
+		t.Errorf("Expected tag imported, got %v", local.Private[0].Tags)
+	}
+}
+
+func TestImportSSHConfigCmd_OverwriteShared(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "db-1", Host: "10.0.0.1", User: "admin"},
+	})
+	path := writeSSHConfig(t, importSSHConfig)
+
+	output, err := executeCommand(t, "import", "ssh-config", path, "--shared", "--on-conflict", "overwrite", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, `host: "10.0.0.1" -> "10.0.0.9"`) {
+		t.Errorf("Expected a diff for db-1, got:\n%s", output)
+	}
+
+	home, _ := os.UserHomeDir()
+	shared, err := config.LoadSharedServers(filepath.Join(home, ".sshy"), "servers.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load shared servers: %v", err)
+	}
+	if len(shared) != 3 {
+		t.Fatalf("Expected 3 shared servers, got %d", len(shared))
+	}
+	if shared[0].Name != "db-1" || shared[0].Host != "10.0.0.9" || shared[0].Port != 2222 {
+		t.Errorf("Expected db-1 to be overwritten, got %+v", shared[0])
+	}
+}
+
+func TestImportSSHConfigCmd_SharedIgnoresOtherSources(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	home, _ := os.UserHomeDir()
+	sshyDir := filepath.Join(home, ".sshy")
+	data, _ := yaml.Marshal(models.Servers{{Name: "db-1", Host: "10.1.0.1", User: "ops"}})
+	if err := os.WriteFile(filepath.Join(sshyDir, "platform.yaml"), data, 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	cfg := fmt.Sprintf("servers_path: servers.yaml\nconfig_path: %s\nsources:\n  - name: platform\n    path: platform.yaml\n", sshyDir)
+	if err := os.WriteFile(filepath.Join(sshyDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	path := writeSSHConfig(t, importSSHConfig)
+
+	output, err := executeCommand(t, "import", "ssh-config", path, "--shared", "--on-conflict", "overwrite", "--yes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "+ db-1  admin@10.0.0.9:2222") {
+		t.Errorf("Expected db-1 to be planned against the main servers file only, got:\n%s", output)
+	}
+	platform, _ := config.LoadSharedServers(sshyDir, "platform.yaml")
+	if len(platform) != 1 || platform[0].Host != "10.1.0.1" {
+		t.Errorf("Expected the platform source to be left alone, got %+v", platform)
+	}
+}
+
+func TestImportSSHConfigCmd_DryRun(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	writeSSHConfig(t, importSSHConfig)
+
+	output, err := executeCommand(t, "import", "ssh-config", "--dry-run")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "3 to add") {
+		t.Errorf("Expected 3 servers to add, got:\n%s", output)
+	}
+	local, _ := config.LoadLocalConfig()
+	if len(local.Private) != 0 {
+		t.Errorf("Expected nothing to be written, got %v", local.Private)
+	}
+}
+
+func TestImportSSHConfigCmd_InvalidConflict(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	writeSSHConfig(t, importSSHConfig)
+
+	_, err := executeCommand(t, "import", "ssh-config", "--on-conflict", "merge")
+	if err == nil || !strings.Contains(err.Error(), "invalid --on-conflict") {
+		t.Errorf("Expected invalid --on-conflict error, got %v", err)
+	}
+}
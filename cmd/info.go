@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "Show a server and its cached facts",
+	Long: `Show the effective server record and the facts cached by "sshy facts",
+without connecting to the server. Use --refresh to collect the facts first.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		inventory, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		server, found := findServer(inventory, args[0])
+		if !found {
+			return fmt.Errorf("server not found: %s", args[0])
+		}
+		cache, err := factsCache()
+		if err != nil {
+			return err
+		}
+
+		if refresh {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			_, errs := collectFacts(ctx, inventory, models.Servers{server}, cache, 1, timeout)
+			if errs[0] != nil {
+				return fmt.Errorf("error collecting facts: %w", errs[0])
+			}
+		}
+
+		data, err := yaml.Marshal(server)
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		fmt.Fprint(out, string(data))
+
+		f, found, err := cache.Load(server.Name)
+		if err != nil {
+			return fmt.Errorf("error loading facts: %w", err)
+		}
+		fmt.Fprintln(out)
+		if !found {
+			fmt.Fprintf(out, "No facts cached. Run 'sshy info %s --refresh' to collect them.\n", server.Name)
+			return nil
+		}
+		printFactsDetails(out, f, time.Now())
+		return nil
+	},
+}
+
+// serverPreview renders the picker preview for a server: its address, tags
+// and cached facts.
+func serverPreview(s models.Server, cached map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%s", s.Name, sshcmd.UserHost(s, s.User))
+	if s.Port != 0 && s.Port != 22 {
+		fmt.Fprintf(&b, ":%d", s.Port)
+	}
+	b.WriteString("\n")
+	if len(s.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n", strings.Join(s.Tags, ", "))
+	}
+	if len(s.Jump) > 0 {
+		fmt.Fprintf(&b, "Jump: %s\n", strings.Join(s.Jump, " -> "))
+	}
+	b.WriteString("\n")
+	if details, ok := cached[s.Name]; ok {
+		b.WriteString(details)
+	} else {
+		b.WriteString("No cached facts\n")
+	}
+	return b.String()
+}
+
+// loadCachedFactsDetails renders the cached facts of servers for previews,
+// keyed by server name. Servers without facts are left out.
+func loadCachedFactsDetails(servers models.Servers) map[string]string {
+	details := make(map[string]string)
+	cache, err := factsCache()
+	if err != nil {
+		return details
+	}
+	now := time.Now()
+	for _, s := range servers {
+		f, found, err := cache.Load(s.Name)
+		if err != nil || !found {
+			continue
+		}
+		var b strings.Builder
+		printFactsDetails(&b, f, now)
+		details[s.Name] = b.String()
+	}
+	return details
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+
+	infoCmd.Flags().Bool("refresh", false, "Collect facts from the server before showing them")
+	infoCmd.Flags().Duration("timeout", 30*time.Second, "Timeout when collecting facts")
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/facts"
 	"github.com/omisai-tech/sshy/internal/models"
 	"github.com/spf13/cobra"
 )
@@ -19,9 +21,16 @@ Server prefixes indicate source:
 - [L] Local private servers from local.yaml
 - [O] Shared servers with local overrides in local.yaml
 
-Use --tags to filter by tags.`,
+Servers from the additional sources of the global config show the source
+name, as in [S:platform]. Names defined by several sources are reported.
+
+Use --tags to filter by tags. Use --check to probe each server and show
+whether it is up or down, and --facts to append the facts cached by
+"sshy facts".`,
 	Run: func(cmd *cobra.Command, args []string) {
 		tags, _ := cmd.Flags().GetStringSlice("tags")
+		check, _ := cmd.Flags().GetBool("check")
+		showFacts, _ := cmd.Flags().GetBool("facts")
 
 		cfg, err := config.LoadGlobalConfig()
 		if err != nil {
@@ -29,26 +38,73 @@ Use --tags to filter by tags.`,
 			return
 		}
 
-		serversWithSource, err := config.LoadServersWithSourceAndPath(cfg.ConfigPath, cfg.GetServersSource())
+		serversWithSource, collisions, err := config.LoadSources(cfg)
 		if err != nil {
 			fmt.Println("Error loading servers:", err)
 			return
 		}
 
+		var filtered []models.ServerWithSource
 		for _, sws := range serversWithSource {
+			if len(tags) == 0 || hasAllTags(sws.Server.Tags, tags) {
+				filtered = append(filtered, sws)
+			}
+		}
+
+		var status []string
+		if check {
+			inventory := make(models.Servers, len(serversWithSource))
+			for i, sws := range serversWithSource {
+				inventory[i] = sws.Server
+			}
+			selected := make(models.Servers, len(filtered))
+			for i, sws := range filtered {
+				selected[i] = sws.Server
+			}
+			for _, r := range checkServers(context.Background(), inventory, selected, 20, defaultCheckTimeout) {
+				if r.Up() {
+					status = append(status, "up  ")
+				} else {
+					status = append(status, "down")
+				}
+			}
+		}
+
+		var cache *facts.Cache
+		if showFacts {
+			if cache, err = factsCache(); err != nil {
+				fmt.Println("Error loading facts:", err)
+				return
+			}
+		}
+
+		for i, sws := range filtered {
 			s := sws.Server
-			if len(tags) == 0 || hasAllTags(s.Tags, tags) {
-				sourceFlag := ""
-				switch sws.Source {
-				case models.SourceShared:
-					sourceFlag = "[S]"
-				case models.SourceLocal:
-					sourceFlag = "[L]"
-				case models.SourceOverride:
-					sourceFlag = "[O]"
+			sourceFlag := ""
+			switch sws.Source {
+			case models.SourceShared:
+				sourceFlag = "[S]"
+			case models.SourceLocal:
+				sourceFlag = "[L]"
+			case models.SourceOverride:
+				sourceFlag = "[O]"
+			}
+			if sws.Origin != "" {
+				sourceFlag = sourceFlag[:2] + ":" + sws.Origin + "]"
+			}
+			if check {
+				sourceFlag += " " + status[i]
+			}
+			line := fmt.Sprintf("%s %s: %s@%s [%s]", sourceFlag, s.Name, s.User, s.Host, strings.Join(s.Tags, ", "))
+			if cache != nil {
+				if f, found, err := cache.Load(s.Name); err == nil && found {
+					line += " - " + f.Summary()
 				}
-				fmt.Printf("%s %s: %s@%s [%s]\n", sourceFlag, s.Name, s.User, s.Host, strings.Join(s.Tags, ", "))
 			}
+			fmt.Fprintln(cmd.OutOrStdout(), line)
+		}
+		for _, c := range collisions {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s is defined in %s and %s; using the one from %s\n", c.Name, c.Kept, c.Dropped, c.Kept)
 		}
 	},
 }
@@ -70,4 +126,6 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 
 	listCmd.Flags().StringSliceP("tags", "t", []string{}, "Filter servers by tags (comma-separated)")
+	listCmd.Flags().Bool("check", false, "Probe each server and show whether it is up or down")
+	listCmd.Flags().Bool("facts", false, "Show cached facts (OS, kernel, CPUs, memory) for each server")
 }
@@ -1,6 +1,15 @@
 package cmd
 
-import "testing"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"gopkg.in/yaml.v3"
+)
 
 func TestHasAllTags(t *testing.T) {
 	tests := []struct {
@@ -80,3 +89,43 @@ func TestHasAllTags(t *testing.T) {
 		})
 	}
 }
+
+func TestListCmd_Sources(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "web-1", Host: "10.0.0.1", User: "deploy"}})
+	home, _ := os.UserHomeDir()
+	sshyDir := filepath.Join(home, ".sshy")
+	data, _ := yaml.Marshal(models.Servers{
+		{Name: "web-1", Host: "10.1.0.1", User: "ops"},
+		{Name: "db-1", Host: "10.1.0.2", User: "ops"},
+	})
+	if err := os.WriteFile(filepath.Join(sshyDir, "data.yaml"), data, 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	cfg := fmt.Sprintf(`servers_path: servers.yaml
+config_path: %s
+sources:
+  - name: data
+    path: data.yaml
+  - name: analytics
+    path: data.yaml
+    namespace: analytics
+`, sshyDir)
+	if err := os.WriteFile(filepath.Join(sshyDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	output, err := executeCommand(t, "list")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"[S] web-1: deploy@10.0.0.1",
+		"[S:data] db-1: ops@10.1.0.2",
+		"[S:analytics] analytics/web-1: ops@10.1.0.1",
+		"Warning: web-1 is defined in shared and data; using the one from shared",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/spf13/cobra"
+)
+
+// Reconnect delays double from the first to the second; a connection that
+// stayed up longer than the maximum starts over at the first.
+var (
+	logsReconnectDelay    = time.Second
+	logsMaxReconnectDelay = 30 * time.Second
+)
+
+// logColors are ANSI foreground colours assigned to servers in turn.
+var logColors = []string{"36", "32", "33", "34", "35", "31", "96", "92", "93", "94", "95", "91"}
+
+type logsOptions struct {
+	Paths []string
+	Lines int
+	// Since drops lines with a timestamp older than this, until the first
+	// line inside the window.
+	Since time.Duration
+	Grep  *regexp.Regexp
+	Color bool
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [names...] [--tags tags] <paths...>",
+	Short: "Follow log files on several servers at once",
+	Long: `Run "tail -F" on every selected server and interleave the lines locally, each
+prefixed with the server name and coloured per server.
+
+Arguments containing a "/" are remote paths, the others are server names; put
+paths after "--" to make the split explicit. Quote globs so that the remote
+shell expands them. Lost connections are re-established with backoff and
+follow only new lines, so lines written while a server was unreachable are
+not shown.
+
+--grep keeps only lines matching a regular expression. --since shows history
+from the given window, based on the timestamps in the lines.`,
+	Example: `  sshy logs --tags api '/var/log/app/*.log'
+  sshy logs web-1 web-2 /var/log/nginx/error.log --grep 'upstream|timeout'
+  sshy logs --tags api --since 15m -- /var/log/app/app.log`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, paths := splitLogsArgs(args, cmd.ArgsLenAtDash())
+		if len(paths) == 0 {
+			return fmt.Errorf("usage: sshy logs [names...] [--tags tags] <paths...>")
+		}
+		tags, all := selectionFromFlags(cmd)
+		opts := logsOptions{Paths: paths}
+		opts.Lines, _ = cmd.Flags().GetInt("lines")
+		opts.Since, _ = cmd.Flags().GetDuration("since")
+		if opts.Since > 0 && !cmd.Flags().Changed("lines") {
+			opts.Lines = 10000
+		}
+		if pattern, _ := cmd.Flags().GetString("grep"); pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid --grep pattern: %w", err)
+			}
+			opts.Grep = re
+		}
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		opts.Color = !noColor && os.Getenv("NO_COLOR") == "" && isTerminal(cmd.OutOrStdout())
+
+		inventory, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		selected, err := selectServers(inventory, names, tags, all)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		followLogs(ctx, inventory, selected, opts, time.Now(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		return nil
+	},
+}
+
+// splitLogsArgs separates server names from remote paths. Everything after
+// "--" is a path; before it, arguments containing a slash or starting with
+// "~" are paths.
+func splitLogsArgs(args []string, dash int) (names, paths []string) {
+	if dash >= 0 {
+		return args[:dash], args[dash:]
+	}
+	for _, arg := range args {
+		if strings.Contains(arg, "/") || strings.HasPrefix(arg, "~") {
+			paths = append(paths, arg)
+		} else {
+			names = append(names, arg)
+		}
+	}
+	return names, paths
+}
+
+// tailCommand leaves the paths unquoted so that the remote shell expands
+// globs.
+func tailCommand(paths []string, lines int) string {
+	return fmt.Sprintf("tail -n %d -F %s", lines, strings.Join(paths, " "))
+}
+
+// followLogs tails the logs on all servers until ctx is done.
+func followLogs(ctx context.Context, inventory, servers models.Servers, opts logsOptions, now time.Time, stdout, stderr io.Writer) {
+	width := 0
+	for _, s := range servers {
+		width = max(width, len(s.Name))
+	}
+
+	var mu sync.Mutex
+	forEachServer(ctx, servers, len(servers), func(ctx context.Context, i int, s models.Server) {
+		prefix := fmt.Sprintf("%-*s", width, s.Name)
+		if opts.Color {
+			prefix = "\x1b[" + logColors[i%len(logColors)] + "m" + prefix + "\x1b[0m"
+		}
+		prefix += " | "
+
+		out := newPrefixWriter(&mu, stdout, prefix)
+		out.keep = logLineFilter(opts, now)
+		errOut := newPrefixWriter(&mu, stderr, prefix)
+		followServer(ctx, inventory, s, opts, out, errOut)
+	})
+}
+
+func followServer(ctx context.Context, inventory models.Servers, s models.Server, opts logsOptions, out, errOut *prefixWriter) {
+	target, err := sshcmd.NewTarget(s, inventory)
+	if err != nil {
+		fmt.Fprintf(errOut, "%v\n", err)
+		return
+	}
+	flags := []string{"-T", "-o", "BatchMode=yes", "-o", "ServerAliveInterval=15", "-o", "ServerAliveCountMax=3"}
+
+	lines := opts.Lines
+	delay := logsReconnectDelay
+	for {
+		inv, err := sshcmd.SSH(target, flags, tailCommand(opts.Paths, lines))
+		if err != nil {
+			fmt.Fprintf(errOut, "%v\n", err)
+			return
+		}
+		started := time.Now()
+		err = cmdRunner.RunContext(ctx, inv.Program, inv.Args, out, errOut)
+		out.Flush()
+		errOut.Flush()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > logsMaxReconnectDelay {
+			delay = logsReconnectDelay
+		}
+		reason := "connection closed"
+		if err != nil {
+			reason = fmt.Sprintf("connection lost (%v)", err)
+		}
+		fmt.Fprintf(errOut, "%s, reconnecting in %s\n", reason, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = min(delay*2, logsMaxReconnectDelay)
+		// The history was already shown; only follow new lines from now on.
+		// Lines written while disconnected are skipped rather than repeated.
+		lines = 0
+	}
+}
+
+// logLineFilter combines the --since window and the --grep pattern. The
+// window is checked on every line, because it is stateful: once a line inside
+// the window is seen, everything after it is kept, including lines without a
+// timestamp.
+func logLineFilter(opts logsOptions, now time.Time) func(line []byte) bool {
+	inWindow := opts.Since <= 0
+	cutoff := now.Add(-opts.Since)
+	return func(line []byte) bool {
+		if !inWindow {
+			ts, ok := parseLogTimestamp(string(line), now)
+			if !ok || ts.Before(cutoff) {
+				return false
+			}
+			inWindow = true
+		}
+		return opts.Grep == nil || opts.Grep.Match(line)
+	}
+}
+
+var logTimestampFormats = []struct {
+	pattern *regexp.Regexp
+	layouts []string
+}{
+	{
+		regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`),
+		[]string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999Z0700", "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999Z0700", "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999"},
+	},
+	{
+		// Common and combined log format, as written by nginx and Apache.
+		regexp.MustCompile(`\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`),
+		[]string{"02/Jan/2006:15:04:05 -0700"},
+	},
+	{
+		// Traditional syslog, which has no year.
+		regexp.MustCompile(`^[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}`),
+		[]string{time.Stamp},
+	},
+}
+
+// parseLogTimestamp finds the first timestamp in a log line. Timestamps
+// without a zone are taken as local time; syslog timestamps get the year of
+// now, or the year before if that would put them in the future.
+func parseLogTimestamp(line string, now time.Time) (time.Time, bool) {
+	for _, format := range logTimestampFormats {
+		match := format.pattern.FindString(line)
+		if match == "" {
+			continue
+		}
+		for _, layout := range format.layouts {
+			ts, err := time.ParseInLocation(layout, match, now.Location())
+			if err != nil {
+				continue
+			}
+			if layout == time.Stamp {
+				ts = ts.AddDate(now.Year(), 0, 0)
+				if ts.After(now.Add(24 * time.Hour)) {
+					ts = ts.AddDate(-1, 0, 0)
+				}
+			}
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isTerminal reports whether w is a terminal, for deciding on colours.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	addSelectionFlags(logsCmd)
+	logsCmd.Flags().IntP("lines", "n", 10, "Number of existing lines to show from each file")
+	logsCmd.Flags().Duration("since", 0, "Show history from this window, e.g. 15m (reads up to 10000 lines unless --lines is set)")
+	logsCmd.Flags().String("grep", "", "Only show lines matching this regular expression")
+	logsCmd.Flags().Bool("no-color", false, "Disable coloured server names")
+}
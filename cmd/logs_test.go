@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestSplitLogsArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		dash          int
+		expectedNames string
+		expectedPaths string
+	}{
+		{"paths only", []string{"/var/log/app/*.log"}, -1, "", "/var/log/app/*.log"},
+		{"names and paths", []string{"web-1", "/var/log/syslog", "web-2", "~/app.log"}, -1, "web-1,web-2", "/var/log/syslog,~/app.log"},
+		{"dash", []string{"web-1", "app.log"}, 1, "web-1", "app.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, paths := splitLogsArgs(tt.args, tt.dash)
+			if strings.Join(names, ",") != tt.expectedNames || strings.Join(paths, ",") != tt.expectedPaths {
+				t.Errorf("splitLogsArgs(%v) = %v, %v", tt.args, names, paths)
+			}
+		})
+	}
+}
+
+func TestParseLogTimestamp(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		line     string
+		expected time.Time
+		ok       bool
+	}{
+		{"rfc3339", `{"time":"2026-01-10T11:30:00Z","msg":"ok"}`, time.Date(2026, 1, 10, 11, 30, 0, 0, time.UTC), true},
+		{"rfc3339 offset", "2026-01-10T12:30:00.123+01:00 INFO started", time.Date(2026, 1, 10, 11, 30, 0, 123000000, time.UTC), true},
+		{"space separated", "2026-01-10 11:45:10,001 WARN slow", time.Date(2026, 1, 10, 11, 45, 10, 0, time.UTC), true},
+		{"common log", `10.0.0.1 - - [10/Jan/2026:11:00:00 +0000] "GET / HTTP/1.1" 200`, time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC), true},
+		{"syslog", "Jan 10 11:59:00 web-1 sshd[123]: Accepted publickey", time.Date(2026, 1, 10, 11, 59, 0, 0, time.UTC), true},
+		{"syslog last year", "Dec 31 23:00:00 web-1 cron[1]: job", time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC), true},
+		{"none", "    at com.example.Handler.run(Handler.java:42)", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, ok := parseLogTimestamp(tt.line, now)
+			if ok != tt.ok {
+				t.Fatalf("parseLogTimestamp(%q) ok = %v, expected %v", tt.line, ok, tt.ok)
+			}
+			if ok && !ts.Equal(tt.expected) {
+				t.Errorf("parseLogTimestamp(%q) = %v, expected %v", tt.line, ts, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLogLineFilter(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	lines := []string{
+		"2026-01-10T11:00:00Z ERROR old failure",
+		"    continuation of old failure",
+		"2026-01-10T11:50:00Z INFO recent",
+		"    continuation of recent",
+		"2026-01-10T11:55:00Z ERROR recent failure",
+	}
+
+	tests := []struct {
+		name     string
+		opts     logsOptions
+		expected []string
+	}{
+		{"no filter", logsOptions{}, lines},
+		{"since", logsOptions{Since: 15 * time.Minute}, lines[2:]},
+		{"grep", logsOptions{Grep: regexp.MustCompile("ERROR")}, []string{lines[0], lines[4]}},
+		{"since and grep", logsOptions{Since: 15 * time.Minute, Grep: regexp.MustCompile("ERROR")}, []string{lines[4]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep := logLineFilter(tt.opts, now)
+			var kept []string
+			for _, line := range lines {
+				if keep([]byte(line)) {
+					kept = append(kept, line)
+				}
+			}
+			if strings.Join(kept, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(kept, "\n"))
+			}
+		})
+	}
+}
+
+func TestFollowLogs(t *testing.T) {
+	oldDelay := logsReconnectDelay
+	logsReconnectDelay = time.Millisecond
+	defer func() { logsReconnectDelay = oldDelay }()
+
+	servers := models.Servers{{Name: "api-1", Host: "10.0.0.1"}, {Name: "api-10", Host: "10.0.0.2"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	calls := map[string][]string{}
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	cmdRunner = &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			host := args[len(args)-2]
+			mu.Lock()
+			calls[host] = append(calls[host], args[len(args)-1])
+			attempt := len(calls[host])
+			done := len(calls["10.0.0.1"]) >= 2 && len(calls["10.0.0.2"]) >= 1
+			mu.Unlock()
+
+			switch {
+			case host == "10.0.0.1" && attempt == 1:
+				fmt.Fprintln(stdout, "first line")
+				return errors.New("exit status 255")
+			case host == "10.0.0.1":
+				fmt.Fprintln(stdout, "after reconnect")
+			default:
+				fmt.Fprintln(stdout, "other server")
+			}
+			if done {
+				cancel()
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	var stdout, stderr bytes.Buffer
+	opts := logsOptions{Paths: []string{"/var/log/app/*.log"}, Lines: 10}
+	followLogs(ctx, servers, servers, opts, time.Now(), &stdout, &stderr)
+
+	for _, expected := range []string{"api-1  | first line\n", "api-1  | after reconnect\n", "api-10 | other server\n"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, stdout.String())
+		}
+	}
+	if !strings.Contains(stderr.String(), "api-1  | connection lost (exit status 255), reconnecting in 1ms") {
+		t.Errorf("Expected reconnect message, got:\n%s", stderr.String())
+	}
+	if got := calls["10.0.0.1"]; len(got) < 2 || got[0] != "tail -n 10 -F /var/log/app/*.log" || got[1] != "tail -n 0 -F /var/log/app/*.log" {
+		t.Errorf("Expected the reconnect to follow new lines only, got %v", got)
+	}
+}
+
+func TestFollowLogs_Color(t *testing.T) {
+	servers := models.Servers{{Name: "api-1", Host: "10.0.0.1"}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	cmdRunner = &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			fmt.Fprintln(stdout, "hello")
+			cancel()
+			return ctx.Err()
+		},
+	}
+
+	var stdout bytes.Buffer
+	followLogs(ctx, servers, servers, logsOptions{Paths: []string{"/tmp/a.log"}, Color: true}, time.Now(), &stdout, io.Discard)
+	if stdout.String() != "\x1b[36mapi-1\x1b[0m | hello\n" {
+		t.Errorf("Expected coloured prefix, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/probe"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/spf13/cobra"
+)
+
+// defaultCheckTimeout is used by list --check and the connect picker.
+const defaultCheckTimeout = 3 * time.Second
+
+type pingResult struct {
+	probe.Result
+	Name string
+	// Via names the jump hosts the probe went through, if any.
+	Via []string
+}
+
+var pingCmd = &cobra.Command{
+	Use:   "ping [names...]",
+	Short: "Check that servers are reachable",
+	Long: `Open a connection to each selected server's SSH port concurrently and read
+its identification banner. For every server the latency and the SSH version,
+or the error, is reported.
+
+Servers behind jump hosts are probed through the jump chain with ssh -W, so the
+latency includes the connection to the jump hosts. Without names, --tags or
+--all, every server is checked. The command exits non-zero if any server is
+unreachable.`,
+	Example: `  sshy ping
+  sshy ping web-1 db-1
+  sshy ping --tags prod --timeout 2s`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		tags, all := selectionFromFlags(cmd)
+
+		inventory, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 && len(tags) == 0 {
+			all = true
+		}
+		selected, err := selectServers(inventory, args, tags, all)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		results := checkServers(ctx, inventory, selected, concurrency, timeout)
+		printPingTable(cmd.OutOrStdout(), results)
+
+		down := 0
+		for _, r := range results {
+			if !r.Up() {
+				down++
+			}
+		}
+		if down > 0 {
+			return fmt.Errorf("%d of %d servers unreachable", down, len(results))
+		}
+		return nil
+	},
+}
+
+// checkServers probes servers concurrently and returns one result per server,
+// in the same order.
+func checkServers(ctx context.Context, inventory, servers models.Servers, concurrency int, timeout time.Duration) []pingResult {
+	results := make([]pingResult, len(servers))
+	forEachServer(ctx, servers, concurrency, func(ctx context.Context, i int, s models.Server) {
+		results[i] = pingServer(ctx, inventory, s, timeout)
+	})
+	for i, s := range servers {
+		if results[i].Name == "" {
+			results[i] = pingResult{Name: s.Name, Result: probe.Result{Address: probe.Address(s.Host, s.Port), Err: errors.New(errInterrupted)}}
+		}
+	}
+	return results
+}
+
+func pingServer(ctx context.Context, inventory models.Servers, s models.Server, timeout time.Duration) pingResult {
+	result := pingResult{Name: s.Name}
+	target, err := sshcmd.NewTarget(s, inventory)
+	if err != nil {
+		result.Address = probe.Address(s.Host, s.Port)
+		result.Err = err
+		return result
+	}
+	if len(target.Hops) == 0 {
+		result.Result = probe.Dial(ctx, probe.Address(s.Host, s.Port), timeout)
+		return result
+	}
+	for _, hop := range target.Hops {
+		result.Via = append(result.Via, hop.Name)
+	}
+	result.Result = probeThroughJump(ctx, target, timeout)
+	return result
+}
+
+// probeThroughJump reads the target's banner over "ssh -W" to the last jump
+// host, which is itself reached through the earlier hops.
+func probeThroughJump(ctx context.Context, t sshcmd.Target, timeout time.Duration) probe.Result {
+	address := probe.Address(t.Server.Host, t.Server.Port)
+	result := probe.Result{Address: address}
+
+	last := len(t.Hops) - 1
+	jump := sshcmd.Target{Server: t.Hops[last], Hops: t.Hops[:last]}
+	inv, err := sshcmd.StdioForward(jump, []string{"-o", "BatchMode=yes"}, address)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+
+	reader, writer := io.Pipe()
+	var stderr bytes.Buffer
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		defer close(done)
+		err := cmdRunner.RunContext(runCtx, inv.Program, inv.Args, writer, &stderr)
+		if err == nil {
+			err = io.EOF
+		}
+		writer.CloseWithError(err)
+	}()
+
+	result.Banner, result.Err = probe.ReadBanner(reader)
+	result.Latency = time.Since(start)
+	timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	cancelRun()
+	reader.Close()
+	<-done
+
+	if result.Err != nil {
+		switch {
+		case timedOut:
+			result.Err = errors.New("timed out")
+		case lastLine(stderr.String()) != "":
+			result.Err = errors.New(lastLine(stderr.String()))
+		}
+	}
+	return result
+}
+
+func printPingTable(w io.Writer, results []pingResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tADDRESS\tSTATUS\tLATENCY\tDETAILS")
+	up := 0
+	for _, r := range results {
+		address := r.Address
+		if len(r.Via) > 0 {
+			address += " via " + strings.Join(r.Via, ",")
+		}
+		if r.Up() {
+			up++
+			fmt.Fprintf(tw, "%s\t%s\tup\t%s\t%s\n", r.Name, address, r.Latency.Round(time.Millisecond), probe.Version(r.Banner))
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\tdown\t-\t%s\n", r.Name, address, r.Err)
+		}
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d up, %d down\n", up, len(results)-up)
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+
+	addSelectionFlags(pingCmd)
+	pingCmd.Flags().IntP("concurrency", "c", 20, "Maximum number of servers to probe at once")
+	pingCmd.Flags().Duration("timeout", 5*time.Second, "Per-server timeout")
+}
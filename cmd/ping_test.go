@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+)
+
+// sshListener starts a local listener that greets every connection with an
+// SSH banner and returns its port.
+func sshListener(t *testing.T, banner string) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(conn, "%s\r\n", banner)
+			conn.Close()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func closedPort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	return port
+}
+
+func pingTestServers(t *testing.T) models.Servers {
+	return models.Servers{
+		{Name: "web-1", Host: "127.0.0.1", Port: sshListener(t, "SSH-2.0-OpenSSH_9.6"), Tags: []string{"web"}},
+		{Name: "web-2", Host: "127.0.0.1", Port: closedPort(t), Tags: []string{"web"}},
+		{Name: "bastion", Host: "bastion.example.com", User: "jump"},
+		{Name: "db-1", Host: "10.0.0.5", Jump: models.JumpChain{"bastion"}},
+	}
+}
+
+func TestCheckServers(t *testing.T) {
+	servers := pingTestServers(t)
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			fmt.Fprint(stdout, "SSH-2.0-OpenSSH_8.9\r\n")
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	cmdRunner = mock
+
+	results := checkServers(context.Background(), servers, servers, 4, time.Second)
+
+	if !results[0].Up() || results[0].Banner != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("Expected web-1 to be up, got %+v", results[0])
+	}
+	if results[1].Up() || results[1].Err.Error() != "connection refused" {
+		t.Errorf("Expected web-2 to be refused, got %+v", results[1])
+	}
+	if !results[3].Up() || results[3].Banner != "SSH-2.0-OpenSSH_8.9" || strings.Join(results[3].Via, ",") != "bastion" {
+		t.Errorf("Expected db-1 to be up via bastion, got %+v", results[3])
+	}
+
+	var jumpCall []string
+	for _, call := range mock.Calls {
+		if containsSequence(call, "-W", "10.0.0.5:22") {
+			jumpCall = call
+		}
+	}
+	if jumpCall == nil || jumpCall[len(jumpCall)-1] != "jump@bastion.example.com" {
+		t.Errorf("Expected ssh -W through bastion, got %v", mock.Calls)
+	}
+}
+
+func TestProbeThroughJump_Error(t *testing.T) {
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	cmdRunner = &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			fmt.Fprintln(stderr, "channel 0: open failed: connect failed: Connection refused")
+			fmt.Fprintln(stderr, "stdio forwarding failed")
+			return mockExitError(255)
+		},
+	}
+
+	target := sshcmd.Target{Server: models.Server{Name: "db-1", Host: "10.0.0.5"}, Hops: []models.Server{{Name: "bastion", Host: "bastion"}}}
+	result := probeThroughJump(context.Background(), target, time.Second)
+	if result.Up() || result.Err.Error() != "stdio forwarding failed" {
+		t.Errorf("Expected the last ssh error line, got %v", result.Err)
+	}
+}
+
+func TestPingCmd(t *testing.T) {
+	servers := pingTestServers(t)
+	setupTestServers(t, servers)
+
+	out, err := executeCommand(t, "ping", "--tags", "web", "--timeout", "1s")
+	if err == nil || err.Error() != "1 of 2 servers unreachable" {
+		t.Errorf("Expected unreachable error, got %v", err)
+	}
+	address := "127.0.0.1:" + strconv.Itoa(servers[0].Port)
+	for _, expected := range []string{"web-1", address, "up", "OpenSSH_9.6", "web-2", "down", "connection refused", "1 up, 1 down"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "db-1") {
+		t.Errorf("Expected db-1 not to be probed, got:\n%s", out)
+	}
+}
+
+func TestConnectCmd_ReachablePicker(t *testing.T) {
+	servers := pingTestServers(t)[:2]
+	setupTestServers(t, servers)
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	oldFuzzy := fuzzyFind
+	defer func() { fuzzyFind = oldFuzzy }()
+
+	tests := []struct {
+		flag     string
+		expected []string
+	}{
+		{"--reachable", []string{"web-1"}},
+		{"--check", []string{"web-1", "web-2 (unreachable: connection refused)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			var shown []string
+			fuzzyFind = func(names []string, itemFunc func(int) string, preview func(int) string) (int, error) {
+				shown = names
+				return -1, errors.New("cancelled")
+			}
+			if err := connectCmd.RunE(connectCmd, []string{tt.flag}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(shown, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Expected picker entries %v, got %v", tt.expected, shown)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/probe"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/omisai-tech/sshy/internal/sshconfig"
+	"github.com/spf13/cobra"
+)
+
+// proxyConfigFile is the file under ~/.sshy, included by the snippet of
+// "sshy ssh-config install", that carries the user, key, port and options of
+// the servers reached through "sshy proxy".
+const proxyConfigFile = "ssh_config"
+
+var proxyDial = func(address string) (net.Conn, error) {
+	return net.DialTimeout("tcp", address, 10*time.Second)
+}
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy <name>",
+	Short: "Connect stdin and stdout to a server, for use as ssh's ProxyCommand",
+	Long: `Resolve a server from the inventory and connect stdin and stdout to its
+SSH port, through its jump chain if it has one. This is meant to be used as
+ssh's ProxyCommand, see "sshy ssh-config install". Remote servers lists are
+read from the cache and never fetched; any other command refreshes them.
+
+With --test, exit zero if the name is a server in the inventory and non-zero
+otherwise, printing nothing, for use with "Match exec". It also refreshes
+~/.sshy/ssh_config with the settings of every server.`,
+	Hidden:       true,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		test, _ := cmd.Flags().GetBool("test")
+		// ssh runs this for every connection; remote lists come from the
+		// cache so that it neither waits on the network nor warns.
+		config.Offline = true
+		defer func() { config.Offline = false }()
+
+		cfg, err := config.LoadGlobalConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+		servers, err := config.LoadServersFromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("error loading servers: %w", err)
+		}
+		server, found := findServer(servers, args[0])
+
+		if test {
+			// ssh runs this for every host it connects to; stay quiet.
+			cmd.SilenceErrors = true
+			if !found {
+				return fmt.Errorf("server not found: %s", args[0])
+			}
+			return writeProxyConfig(servers)
+		}
+
+		if !found {
+			return fmt.Errorf("server not found: %s", args[0])
+		}
+		target, err := sshcmd.NewTarget(server, servers)
+		if err != nil {
+			return err
+		}
+		return proxyTo(target, cmd.InOrStdin(), cmd.OutOrStdout())
+	},
+}
+
+// proxyTo splices stdin and stdout to the target's SSH port. Through jump
+// hosts this is "ssh -W" to the last hop, which ssh itself splices.
+func proxyTo(t sshcmd.Target, stdin io.Reader, stdout io.Writer) error {
+	address := probe.Address(t.Server.Host, t.Server.Port)
+	if len(t.Hops) > 0 {
+		last := len(t.Hops) - 1
+		jump := sshcmd.Target{Server: t.Hops[last], Hops: t.Hops[:last]}
+		inv, err := sshcmd.StdioForward(jump, nil, address)
+		if err != nil {
+			return err
+		}
+		return cmdRunner.Run(inv.Program, inv.Args)
+	}
+
+	conn, err := proxyDial(address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return splice(conn, stdin, stdout)
+}
+
+// splice copies in both directions until the remote side closes. When stdin
+// ends first, the write side of conn is closed so the server sees EOF.
+func splice(conn net.Conn, stdin io.Reader, stdout io.Writer) error {
+	go func() {
+		io.Copy(conn, stdin)
+		if c, ok := conn.(interface{ CloseWrite() error }); ok {
+			c.CloseWrite()
+		}
+	}()
+	_, err := io.Copy(stdout, conn)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// proxyConfig renders the servers for ~/.sshy/ssh_config. Jump chains are
+// left out because "sshy proxy" handles them.
+func proxyConfig(servers models.Servers) (string, error) {
+	direct := make(models.Servers, len(servers))
+	for i, s := range servers {
+		s.Jump = nil
+		if s.Options != nil {
+			options := make(map[string]interface{}, len(s.Options))
+			for key, value := range s.Options {
+				if !strings.EqualFold(key, "ProxyJump") {
+					options[key] = value
+				}
+			}
+			s.Options = options
+		}
+		direct[i] = s
+	}
+	rendered, err := sshconfig.Render(direct)
+	if err != nil {
+		return "", err
+	}
+	return "# Generated by sshy for \"sshy proxy\". Changes here are overwritten.\n\n" + rendered, nil
+}
+
+// writeProxyConfig refreshes ~/.sshy/ssh_config when it is out of date.
+func writeProxyConfig(servers models.Servers) error {
+	content, err := proxyConfig(servers)
+	if err != nil {
+		return err
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, proxyConfigFile)
+	if current, err := os.ReadFile(path); err == nil && string(current) == content {
+		return nil
+	}
+	return writeFileAtomic(path, []byte(content), 0600)
+}
+
+func init() {
+	rootCmd.AddCommand(proxyCmd)
+
+	proxyCmd.Flags().Bool("test", false, "Only report whether the name is a server in the inventory")
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+)
+
+func TestProxyTo_Direct(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		conn.Write(append([]byte("echo: "), data...))
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	target := sshcmd.Target{Server: models.Server{Name: "web", Host: "127.0.0.1", Port: addr.Port}}
+	var out bytes.Buffer
+	if err := proxyTo(target, strings.NewReader("SSH-2.0-client\r\n"), &out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.String() != "echo: SSH-2.0-client\r\n" {
+		t.Errorf("Expected the echoed input, got %q", out.String())
+	}
+}
+
+func TestProxyTo_Jump(t *testing.T) {
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	target := sshcmd.Target{
+		Server: models.Server{Name: "db", Host: "10.0.0.5"},
+		Hops: []models.Server{
+			{Name: "edge", Host: "edge.example.com"},
+			{Name: "bastion", Host: "bastion.internal", User: "ops", Port: 2222},
+		},
+	}
+	if err := proxyTo(target, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mock.LastCommand != "ssh" {
+		t.Errorf("Expected ssh, got %s", mock.LastCommand)
+	}
+	if !containsSequence(mock.LastArgs, "-W", "10.0.0.5:22") {
+		t.Errorf("Expected -W 10.0.0.5:22, got %v", mock.LastArgs)
+	}
+	if mock.LastArgs[len(mock.LastArgs)-1] != "ops@bastion.internal" {
+		t.Errorf("Expected to connect to the last hop, got %v", mock.LastArgs)
+	}
+	if !containsSequence(mock.LastArgs, "-p", "2222") {
+		t.Errorf("Expected the last hop's port, got %v", mock.LastArgs)
+	}
+}
+
+func TestProxyCmd_Test(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "bastion", Host: "bastion.example.com"},
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Jump: models.JumpChain{"bastion"}},
+	})
+
+	if _, err := executeCommand(t, "proxy", "--test", "github.com"); err == nil {
+		t.Errorf("Expected --test to fail for an unknown name")
+	}
+	if _, err := executeCommand(t, "proxy", "--test", "web-1"); err != nil {
+		t.Fatalf("Expected --test to pass for web-1, got %v", err)
+	}
+
+	home, _ := os.UserHomeDir()
+	data, err := os.ReadFile(filepath.Join(home, ".sshy", proxyConfigFile))
+	if err != nil {
+		t.Fatalf("Expected the proxy config to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "Host web-1\n  HostName 10.0.0.1\n  User deploy\n") {
+		t.Errorf("Expected web-1 in the proxy config, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "ProxyJump") {
+		t.Errorf("Expected no ProxyJump in the proxy config, got:\n%s", data)
+	}
+}
+
+func TestProxyCmd_TestOffline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("- name: web-1\n  host: 10.0.0.1\n"))
+	}))
+	defer server.Close()
+
+	setupTestServers(t, models.Servers{})
+	home, _ := os.UserHomeDir()
+	cfg := fmt.Sprintf("servers_url: %s/servers.yaml\nconfig_path: %s\n", server.URL, filepath.Join(home, ".sshy"))
+	os.WriteFile(filepath.Join(home, ".sshy", "config.yaml"), []byte(cfg), 0644)
+
+	if _, err := executeCommand(t, "proxy", "--test", "web-1"); err == nil {
+		t.Errorf("Expected --test to fail before the list is cached")
+	}
+	if _, err := executeCommand(t, "list"); err != nil {
+		t.Fatalf("Expected list to fetch the servers, got %v", err)
+	}
+	server.Close()
+
+	output, err := executeCommand(t, "proxy", "--test", "web-1")
+	if err != nil {
+		t.Fatalf("Expected --test to pass from the cache, got %v", err)
+	}
+	if requests != 1 || output != "" {
+		t.Errorf("Expected only list to fetch and --test to print nothing, got %d requests and %q", requests, output)
+	}
+}
+
+func TestSSHConfigInstallCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+	path := writeSSHConfig(t, "Host github.com\n  User git\n")
+
+	if _, err := executeCommand(t, "ssh-config", "install"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, want := range []string{"Match exec \"", " proxy --test %n\"\n", " proxy %n\n", "/.sshy/ssh_config\"\n", "Host github.com\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in ~/.ssh/config, got:\n%s", want, content)
+		}
+	}
+
+	output, err := executeCommand(t, "ssh-config", "install")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "is up to date") {
+		t.Errorf("Expected a second install to change nothing, got:\n%s", output)
+	}
+}
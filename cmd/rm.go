@@ -27,7 +27,7 @@ var rmCmd = &cobra.Command{
 			fmt.Println("Error loading local config:", err)
 			return
 		}
-		serversWithSource, err := config.LoadServersWithSourceAndPath(cfg.ConfigPath, cfg.GetServersSource())
+		serversWithSource, err := config.LoadServersWithSourceFromConfig(cfg)
 		if err != nil {
 			fmt.Println("Error loading servers:", err)
 			return
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+type rollingOptions struct {
+	BatchSize string
+	Pause     time.Duration
+	// Canary runs the first server on its own before any batch; a failure
+	// there always stops the run.
+	Canary      bool
+	FailFast    bool
+	MaxFailures int
+}
+
+// parseBatchSize accepts a server count ("5") or a share of total ("25%"),
+// rounded up so a batch is never empty.
+func parseBatchSize(value string, total int) (int, error) {
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.Atoi(percent)
+		if err != nil || p < 1 || p > 100 {
+			return 0, fmt.Errorf("invalid batch size %q: percentage must be between 1%% and 100%%", value)
+		}
+		return max(1, (total*p+99)/100), nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid batch size %q: use a positive number or a percentage", value)
+	}
+	return n, nil
+}
+
+// planBatches splits servers into consecutive batches of size, optionally
+// preceded by a single-server canary batch.
+func planBatches(servers models.Servers, size int, canary bool) []models.Servers {
+	var batches []models.Servers
+	if canary && len(servers) > 0 {
+		batches = append(batches, servers[:1])
+		servers = servers[1:]
+	}
+	for len(servers) > 0 {
+		n := min(size, len(servers))
+		batches = append(batches, servers[:n])
+		servers = servers[n:]
+	}
+	return batches
+}
+
+// runRolling runs command batch by batch, reporting progress to progress.
+// Servers in batches that never ran are reported as not started.
+func runRolling(ctx context.Context, inventory, servers models.Servers, command string, opts execOptions, rolling rollingOptions, stdout, stderr, progress io.Writer) ([]hostResult, error) {
+	size, err := parseBatchSize(rolling.BatchSize, len(servers))
+	if err != nil {
+		return nil, err
+	}
+	batches := planBatches(servers, size, rolling.Canary)
+	// Each batch runs all at once; the batch size is the concurrency.
+	opts.Concurrency = size
+
+	results := make([]hostResult, 0, len(servers))
+	failures := 0
+	stopReason := ""
+	for i, batch := range batches {
+		if i > 0 && rolling.Pause > 0 {
+			fmt.Fprintf(progress, "Pausing %s before the next batch\n", rolling.Pause)
+			select {
+			case <-time.After(rolling.Pause):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		label := fmt.Sprintf("Batch %d/%d", i+1, len(batches))
+		if rolling.Canary && i == 0 {
+			label = "Canary"
+		}
+		fmt.Fprintf(progress, "==> %s: %s\n", label, strings.Join(serverNames(batch), ", "))
+
+		batchResults := runOnServers(ctx, inventory, batch, command, opts, stdout, stderr)
+		results = append(results, batchResults...)
+
+		batchFailures := 0
+		for _, r := range batchResults {
+			if r.failed() {
+				batchFailures++
+			}
+		}
+		failures += batchFailures
+		fmt.Fprintf(progress, "==> %s: %d succeeded, %d failed (%d/%d servers done)\n",
+			label, len(batch)-batchFailures, batchFailures, len(results), len(servers))
+
+		switch {
+		case ctx.Err() != nil:
+		case rolling.Canary && i == 0 && batchFailures > 0:
+			stopReason = "canary failed"
+		case rolling.FailFast && failures > 0:
+			stopReason = "a server failed and --fail-fast is set"
+		case rolling.MaxFailures > 0 && failures >= rolling.MaxFailures:
+			stopReason = fmt.Sprintf("%d failures reached --max-failures %d", failures, rolling.MaxFailures)
+		}
+		if stopReason != "" {
+			break
+		}
+	}
+
+	if stopReason != "" {
+		fmt.Fprintf(progress, "Stopping: %s\n", stopReason)
+	}
+	for _, s := range servers[len(results):] {
+		results = append(results, hostResult{Name: s.Name, Host: s.Host, ExitCode: -1, Error: errNotStarted})
+	}
+	return results, nil
+}
+
+// printProgressReport lists which servers finished and which did not, for
+// runs that were interrupted or stopped early.
+func printProgressReport(w io.Writer, results []hostResult) {
+	var done, notDone []string
+	for _, r := range results {
+		if r.finished() {
+			done = append(done, r.Name)
+		} else {
+			notDone = append(notDone, r.Name)
+		}
+	}
+	if len(notDone) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d of %d servers done\n", len(done), len(results))
+	if len(done) > 0 {
+		fmt.Fprintf(w, "Done: %s\n", strings.Join(done, ", "))
+	}
+	fmt.Fprintf(w, "Not done: %s\n", strings.Join(notDone, ", "))
+}
+
+func serverNames(servers models.Servers) []string {
+	names := make([]string, len(servers))
+	for i, s := range servers {
+		names[i] = s.Name
+	}
+	return names
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestParseBatchSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		total    int
+		expected int
+		wantErr  bool
+	}{
+		{"1", 10, 1, false},
+		{"4", 10, 4, false},
+		{"25%", 10, 3, false},
+		{"50%", 4, 2, false},
+		{"10%", 3, 1, false},
+		{"100%", 7, 7, false},
+		{"0", 10, 0, true},
+		{"-2", 10, 0, true},
+		{"0%", 10, 0, true},
+		{"150%", 10, 0, true},
+		{"abc", 10, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			size, err := parseBatchSize(tt.value, tt.total)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBatchSize(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if size != tt.expected {
+				t.Errorf("parseBatchSize(%q, %d) = %d, expected %d", tt.value, tt.total, size, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPlanBatches(t *testing.T) {
+	servers := models.Servers{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+
+	tests := []struct {
+		name     string
+		size     int
+		canary   bool
+		expected string
+	}{
+		{"one at a time", 1, false, "a|b|c|d|e"},
+		{"pairs", 2, false, "a,b|c,d|e"},
+		{"canary", 2, true, "a|b,c|d,e"},
+		{"larger than inventory", 10, false, "a,b,c,d,e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, batch := range planBatches(servers, tt.size, tt.canary) {
+				got = append(got, strings.Join(serverNames(batch), ","))
+			}
+			if strings.Join(got, "|") != tt.expected {
+				t.Errorf("Expected batches %s, got %s", tt.expected, strings.Join(got, "|"))
+			}
+		})
+	}
+}
+
+func TestRunRolling(t *testing.T) {
+	servers := models.Servers{
+		{Name: "a", Host: "a.internal"},
+		{Name: "b", Host: "b.internal"},
+		{Name: "c", Host: "c.internal"},
+		{Name: "d", Host: "d.internal"},
+		{Name: "e", Host: "e.internal"},
+	}
+
+	tests := []struct {
+		name       string
+		opts       rollingOptions
+		failing    string
+		ran        string
+		stopReason string
+	}{
+		{name: "all batches", opts: rollingOptions{BatchSize: "2"}, failing: "c.internal", ran: "a,b,c,d,e"},
+		{name: "fail fast", opts: rollingOptions{BatchSize: "2", FailFast: true}, failing: "c.internal", ran: "a,b,c,d", stopReason: "--fail-fast"},
+		{name: "canary fails", opts: rollingOptions{BatchSize: "2", Canary: true}, failing: "a.internal", ran: "a", stopReason: "canary failed"},
+		{name: "max failures", opts: rollingOptions{BatchSize: "1", MaxFailures: 2}, failing: "b.internal c.internal d.internal", ran: "a,b,c", stopReason: "--max-failures 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldRunner := cmdRunner
+			defer func() { cmdRunner = oldRunner }()
+			mock := &MockCommandRunner{
+				RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+					if strings.Contains(tt.failing, args[len(args)-2]) {
+						return mockExitError(1)
+					}
+					return nil
+				},
+			}
+			cmdRunner = mock
+
+			var progress strings.Builder
+			results, err := runRolling(context.Background(), servers, servers, "true", execOptions{}, tt.opts, io.Discard, io.Discard, &progress)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(results) != len(servers) {
+				t.Fatalf("Expected a result for every server, got %d", len(results))
+			}
+
+			var ran []string
+			for _, r := range results {
+				if r.finished() {
+					ran = append(ran, r.Name)
+				} else if r.Error != errNotStarted {
+					t.Errorf("Expected %s to be not started, got %q", r.Name, r.Error)
+				}
+			}
+			if strings.Join(ran, ",") != tt.ran {
+				t.Errorf("Expected %s to run, got %s\n%s", tt.ran, strings.Join(ran, ","), progress.String())
+			}
+			if len(mock.Calls) != len(ran) {
+				t.Errorf("Expected %d ssh calls, got %d", len(ran), len(mock.Calls))
+			}
+			if tt.stopReason != "" && !strings.Contains(progress.String(), "Stopping: ") {
+				t.Errorf("Expected a stop message, got:\n%s", progress.String())
+			}
+			if !strings.Contains(progress.String(), tt.stopReason) {
+				t.Errorf("Expected progress to mention %q, got:\n%s", tt.stopReason, progress.String())
+			}
+		})
+	}
+}
+
+func TestRunRolling_Interrupted(t *testing.T) {
+	servers := models.Servers{{Name: "a", Host: "a.internal"}, {Name: "b", Host: "b.internal"}, {Name: "c", Host: "c.internal"}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	cmdRunner = &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			if args[len(args)-2] == "b.internal" {
+				cancel()
+				return fmt.Errorf("signal: killed")
+			}
+			return nil
+		},
+	}
+
+	var progress strings.Builder
+	results, err := runRolling(ctx, servers, servers, "true", execOptions{}, rollingOptions{BatchSize: "1"}, io.Discard, io.Discard, &progress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var report strings.Builder
+	printProgressReport(&report, results)
+	for _, expected := range []string{"1 of 3 servers done", "Done: a\n", "Not done: b, c\n"} {
+		if !strings.Contains(report.String(), expected) {
+			t.Errorf("Expected report to contain %q, got:\n%s", expected, report.String())
+		}
+	}
+}
+
+func TestExecCmd_Rolling(t *testing.T) {
+	setupTestServers(t, execTestServers())
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	cmdRunner = &MockCommandRunner{}
+
+	out, err := executeCommand(t, "exec", "--all", "--canary", "--batch-size", "50%", "--", "true")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"==> Canary: web-1\n", "==> Batch 2/2: web-2, db-1\n", "3 succeeded, 0 failed"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
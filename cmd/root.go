@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/omisai-tech/sshy/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +20,11 @@ var rootCmd = &cobra.Command{
 	Short:   "Manage SSH servers via YAML config",
 	Version: version,
 	Long:    `sshy is a CLI tool for managing SSH servers via YAML configuration.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if refresh, _ := cmd.Flags().GetBool("refresh-servers"); refresh {
+			config.ForceRefresh = true
+		}
+	},
 }
 
 var osExit = os.Exit
@@ -27,8 +34,11 @@ func Execute() {
 }
 
 func ExecuteWithArgs(args []string) {
-	if len(args) == 1 || (len(args) > 1 && !isSubcommand(args[1]) && !isFlag(args[1])) {
-		connectCmd.RunE(connectCmd, args[1:])
+	if len(args) == 1 || (len(args) > 1 && (isPrintFlag(args[1]) || args[1] == refreshServersFlag || !isSubcommand(args[1]) && !isFlag(args[1]))) {
+		if err := connectCmd.RunE(connectCmd, args[1:]); err != nil {
+			fmt.Fprintln(connectCmd.ErrOrStderr(), "Error:", err)
+			osExit(1)
+		}
 		return
 	}
 
@@ -46,10 +56,33 @@ func isSubcommand(arg string) bool {
 	return false
 }
 
+// refreshServersFlag is handled by hand in the commands that parse their own
+// flags.
+const refreshServersFlag = "--refresh-servers"
+
+// extractRefreshFlag removes --refresh-servers from args, forcing remote servers lists
+// to be fetched. Arguments after "--" are left untouched.
+func extractRefreshFlag(args []string) []string {
+	remaining := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			remaining = append(remaining, args[i:]...)
+			break
+		}
+		if arg == refreshServersFlag {
+			config.ForceRefresh = true
+			continue
+		}
+		remaining = append(remaining, arg)
+	}
+	return remaining
+}
+
 func isFlag(arg string) bool {
 	return len(arg) > 1 && arg[0] == '-'
 }
 
 func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().Bool("refresh-servers", false, "Fetch remote servers lists even when the cached copy is fresh")
 }
@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
+	"strings"
 	"testing"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/pflag"
 )
 
 func TestIsSubcommand(t *testing.T) {
@@ -21,6 +27,7 @@ func TestIsSubcommand(t *testing.T) {
 		{"valid command scp", "scp", true},
 		{"valid command sftp", "sftp", true},
 		{"valid command view", "view", true},
+		{"valid command exec", "exec", true},
 		{"invalid command", "invalid", false},
 		{"empty string", "", false},
 		{"random string", "foobar", false},
@@ -62,6 +69,33 @@ func TestIsFlag(t *testing.T) {
 	}
 }
 
+func TestExtractRefreshFlag(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedArgs []string
+		refresh      bool
+	}{
+		{"no flag", []string{"web", "-v"}, []string{"web", "-v"}, false},
+		{"refresh", []string{"--refresh-servers", "web"}, []string{"web"}, true},
+		{"after separator", []string{"web", "--", "tool", "--refresh-servers"}, []string{"web", "--", "tool", "--refresh-servers"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.ForceRefresh = false
+			defer func() { config.ForceRefresh = false }()
+			args := extractRefreshFlag(tt.args)
+			if strings.Join(args, "|") != strings.Join(tt.expectedArgs, "|") {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+			if config.ForceRefresh != tt.refresh {
+				t.Errorf("Expected ForceRefresh %v, got %v", tt.refresh, config.ForceRefresh)
+			}
+		})
+	}
+}
+
 func TestSetVersionInfo(t *testing.T) {
 	SetVersionInfo("1.0.0", "abc123", "2024-01-01")
 
@@ -110,7 +144,7 @@ func TestExecuteWithArgs_NoArgs(t *testing.T) {
 	oldFuzzy := fuzzyFind
 	defer func() { fuzzyFind = oldFuzzy }()
 
-	fuzzyFind = func(names []string, itemFunc func(int) string) (int, error) {
+	fuzzyFind = func(names []string, itemFunc func(int) string, preview func(int) string) (int, error) {
 		if len(names) > 0 {
 			return 0, nil
 		}
@@ -120,6 +154,10 @@ func TestExecuteWithArgs_NoArgs(t *testing.T) {
 	mock := &MockCommandRunner{}
 	cmdRunner = mock
 
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+	osExit = func(code int) {}
+
 	ExecuteWithArgs([]string{"sshy"})
 }
 
@@ -130,16 +168,44 @@ func TestExecuteWithArgs_UnknownArg(t *testing.T) {
 	oldFuzzy := fuzzyFind
 	defer func() { fuzzyFind = oldFuzzy }()
 
-	fuzzyFind = func(names []string, itemFunc func(int) string) (int, error) {
+	fuzzyFind = func(names []string, itemFunc func(int) string, preview func(int) string) (int, error) {
 		return -1, errors.New("cancelled")
 	}
 
 	mock := &MockCommandRunner{}
 	cmdRunner = mock
 
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+	osExit = func(code int) {}
+
 	ExecuteWithArgs([]string{"sshy", "someserver"})
 }
 
+func TestExecuteWithArgs_BareNameError(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web", Host: "web.internal", Options: map[string]interface{}{"NoSuchOption": "yes"}},
+	})
+
+	exitCode := -1
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+	osExit = func(code int) { exitCode = code }
+
+	var stderr bytes.Buffer
+	connectCmd.SetErr(&stderr)
+	defer connectCmd.SetErr(nil)
+
+	ExecuteWithArgs([]string{"sshy", "web", "--print"})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if !strings.HasPrefix(stderr.String(), "Error: ") || !strings.Contains(stderr.String(), "NoSuchOption") {
+		t.Errorf("Expected the error on stderr, got %q", stderr.String())
+	}
+}
+
 func TestExecuteWithArgs_InvalidCommand(t *testing.T) {
 	exitCalled := false
 	exitCode := 0
@@ -169,7 +235,7 @@ func TestExecute(t *testing.T) {
 	oldFuzzy := fuzzyFind
 	defer func() { fuzzyFind = oldFuzzy }()
 
-	fuzzyFind = func(names []string, itemFunc func(int) string) (int, error) {
+	fuzzyFind = func(names []string, itemFunc func(int) string, preview func(int) string) (int, error) {
 		return -1, errors.New("cancelled")
 	}
 
@@ -178,3 +244,30 @@ func TestExecute(t *testing.T) {
 
 	Execute()
 }
+
+func executeCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	rootCmd.SetArgs(args)
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	}()
+
+	if c, _, err := rootCmd.Find(args); err == nil {
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				sv.Replace(nil)
+			} else {
+				f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	}
+
+	_, err := rootCmd.ExecuteC()
+	return out.String(), err
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/probe"
+	"github.com/spf13/cobra"
+)
+
+var lookupAddr = net.DefaultResolver.LookupAddr
+
+type scanHit struct {
+	probe.Hit
+	// DNSName is the reverse DNS name, if looked up and found.
+	DNSName string
+	// Known names the inventory server at this address, if any.
+	Known string
+}
+
+var scanCmd = &cobra.Command{
+	Use:   "scan <network>... [--port ports]",
+	Short: "Find SSH servers in a network",
+	Long: `Probe every address of one or more networks (in CIDR notation, or single
+addresses) for SSH servers and list the ones that answer, with their SSH
+version and whether they are already in the inventory.
+
+Connections are started at most --rate per second, with at most --concurrency
+open at once, and each gives up after --timeout, so that scanning an office
+network stays gentle. Networks are limited to 65536 addresses. --resolve looks
+up the DNS name of every server found.
+
+With --add, pick the new servers to keep (Tab to select, Enter to add); they
+are added to the private servers in your local config with --user and --tag,
+named after their DNS name or address.`,
+	Example: `  sshy scan 10.0.4.0/24
+  sshy scan 10.0.4.0/24 --port 22,2222 --resolve
+  sshy scan 192.168.1.0/24 --add --user admin --tag lab`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ports, _ := cmd.Flags().GetIntSlice("port")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		rate, _ := cmd.Flags().GetInt("rate")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		resolve, _ := cmd.Flags().GetBool("resolve")
+		add, _ := cmd.Flags().GetBool("add")
+		user, _ := cmd.Flags().GetString("user")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		out := cmd.OutOrStdout()
+
+		if len(ports) == 0 {
+			return fmt.Errorf("no ports to probe")
+		}
+		for _, port := range ports {
+			if port < 1 || port > 65535 {
+				return fmt.Errorf("invalid port %d", port)
+			}
+		}
+		if rate < 0 {
+			return fmt.Errorf("--rate must not be negative")
+		}
+		var addrs []netip.Addr
+		for _, arg := range args {
+			expanded, err := probe.ExpandNetwork(arg)
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, expanded...)
+		}
+		if len(addrs) > probe.MaxScanAddresses {
+			return fmt.Errorf("%d addresses to scan, more than %d", len(addrs), probe.MaxScanAddresses)
+		}
+
+		cfg, err := config.LoadGlobalConfig()
+		if err != nil {
+			return fmt.Errorf("error loading config: %w", err)
+		}
+		existing, err := config.LoadServersWithSourceFromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("error loading servers: %w", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		fmt.Fprintf(cmd.ErrOrStderr(), "Scanning %d addresses (ports %s)...\n", len(addrs), joinPorts(ports))
+		// The DNS lookups share the probes' limits.
+		limiter := probe.NewLimiter(concurrency, rate)
+		defer limiter.Stop()
+		found := probe.Scan(ctx, addrs, probe.ScanOptions{Ports: ports, Limiter: limiter, Timeout: timeout})
+		hits := make([]scanHit, len(found))
+		for i, h := range found {
+			hits[i] = scanHit{Hit: h}
+		}
+		if resolve {
+			resolveHits(ctx, hits, limiter, timeout)
+		}
+		var fresh []scanHit
+		for i := range hits {
+			hits[i].Known = knownServer(hits[i], existing)
+			if hits[i].Known == "" {
+				fresh = append(fresh, hits[i])
+			}
+		}
+
+		if len(hits) == 0 {
+			fmt.Fprintln(out, "No SSH servers found")
+			return nil
+		}
+		printScanTable(out, hits)
+		fmt.Fprintf(out, "\n%d SSH servers found, %d new\n", len(hits), len(fresh))
+		if !add || len(fresh) == 0 {
+			return nil
+		}
+
+		labels := make([]string, len(fresh))
+		for i, h := range fresh {
+			labels[i] = fmt.Sprintf("%s  %s  %s", h.Address, h.DNSName, probe.Version(h.Banner))
+		}
+		selected, err := fuzzyFindMulti(labels, func(i int) string { return labels[i] })
+		if err != nil || len(selected) == 0 {
+			fmt.Fprintln(out, "No servers selected")
+			return nil
+		}
+		servers := make(models.Servers, len(selected))
+		for i, idx := range selected {
+			servers[i] = scanServer(fresh[idx], user, tags)
+		}
+		return addPrivateServers(out, cfg, existing, servers)
+	},
+}
+
+// resolveHits looks up the DNS names of the hits concurrently, within the
+// limits of limiter. A zero timeout leaves the lookups unbounded, as it does
+// the probes.
+func resolveHits(ctx context.Context, hits []scanHit, limiter *probe.Limiter, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for i := range hits {
+		if !limiter.Acquire(ctx) {
+			break
+		}
+		wg.Add(1)
+		go func(h *scanHit) {
+			defer wg.Done()
+			defer limiter.Release()
+			lookupCtx := ctx
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				lookupCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			names, err := lookupAddr(lookupCtx, h.Addr.String())
+			if err == nil && len(names) > 0 {
+				h.DNSName = strings.TrimSuffix(names[0], ".")
+			}
+		}(&hits[i])
+	}
+	wg.Wait()
+}
+
+// knownServer returns the name of the server at the hit's address and port,
+// matched by IP address or DNS name.
+func knownServer(h scanHit, existing []models.ServerWithSource) string {
+	for _, s := range existing {
+		port := s.Server.Port
+		if port == 0 {
+			port = 22
+		}
+		if port != h.Port {
+			continue
+		}
+		if s.Server.Host == h.Addr.String() || h.DNSName != "" && strings.EqualFold(s.Server.Host, h.DNSName) {
+			return s.Server.Name
+		}
+	}
+	return ""
+}
+
+// scanServer names a hit after its DNS name or address, with the port
+// appended when it is not 22.
+func scanServer(h scanHit, user string, tags []string) models.Server {
+	name := h.Addr.String()
+	if h.DNSName != "" {
+		name = h.DNSName
+	}
+	s := models.Server{Name: name, Host: h.Addr.String(), User: user, Tags: mergeTags(nil, tags)}
+	if h.Port != 22 {
+		s.Name = fmt.Sprintf("%s-%d", name, h.Port)
+		s.Port = h.Port
+	}
+	return s
+}
+
+func printScanTable(w io.Writer, hits []scanHit) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tNAME\tVERSION\tLATENCY\tINVENTORY")
+	for _, h := range hits {
+		status := "new"
+		if h.Known != "" {
+			status = "known: " + h.Known
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", h.Address, h.DNSName, probe.Version(h.Banner), h.Latency.Round(time.Millisecond), status)
+	}
+	tw.Flush()
+}
+
+func joinPorts(ports []int) string {
+	parts := make([]string, len(ports))
+	for i, p := range ports {
+		parts[i] = strconv.Itoa(p)
+	}
+	return strings.Join(parts, ",")
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+	scanCmd.Flags().IntSlice("port", []int{22}, "Ports to probe, comma separated")
+	scanCmd.Flags().IntP("concurrency", "c", 32, "Maximum number of connections open at once")
+	scanCmd.Flags().Int("rate", 100, "Maximum connections started per second (0 means no limit)")
+	scanCmd.Flags().Duration("timeout", time.Second, "Per-connection timeout")
+	scanCmd.Flags().Bool("resolve", false, "Look up the DNS names of the servers found")
+	scanCmd.Flags().Bool("add", false, "Pick new servers to add to your local config")
+	scanCmd.Flags().String("user", "", "User for the added servers")
+	scanCmd.Flags().StringSlice("tag", []string{}, "Add these tags to every added server")
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/probe"
+)
+
+func TestScanCmd(t *testing.T) {
+	known := sshListener(t, "SSH-2.0-OpenSSH_9.6")
+	fresh := sshListener(t, "SSH-2.0-dropbear_2022.83")
+	closed := closedPort(t)
+	setupTestServers(t, models.Servers{{Name: "web-1", Host: "127.0.0.1", Port: known}})
+
+	oldLookup := lookupAddr
+	defer func() { lookupAddr = oldLookup }()
+	lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		if addr == "127.0.0.1" {
+			return []string{"box.lan."}, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}
+	oldFind := fuzzyFindMulti
+	defer func() { fuzzyFindMulti = oldFind }()
+	var labels []string
+	fuzzyFindMulti = func(items []string, itemFunc func(int) string) ([]int, error) {
+		labels = items
+		return []int{0}, nil
+	}
+
+	ports := fmt.Sprintf("%d,%d,%d", known, fresh, closed)
+	output, err := executeCommand(t, "scan", "127.0.0.0/30", "--port", ports, "--rate", "0", "--resolve",
+		"--add", "--user", "admin", "--tag", "lab")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"known: web-1",
+		"dropbear_2022.83",
+		"box.lan",
+		"2 SSH servers found, 1 new",
+		"+ box.lan-" + strconv.Itoa(fresh),
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "127.0.0.1:"+strconv.Itoa(closed)) {
+		t.Errorf("Expected the closed port to be left out, got:\n%s", output)
+	}
+	if len(labels) != 1 {
+		t.Errorf("Expected only the new server to be offered, got %v", labels)
+	}
+
+	local, err := config.LoadLocalConfig()
+	if err != nil {
+		t.Fatalf("Failed to load local config: %v", err)
+	}
+	if len(local.Private) != 1 {
+		t.Fatalf("Expected 1 private server, got %v", local.Private)
+	}
+	s := local.Private[0]
+	if s.Host != "127.0.0.1" || s.Port != fresh || s.User != "admin" || !contains(s.Tags, "lab") {
+		t.Errorf("Expected the new server with user and tag, got %+v", s)
+	}
+}
+
+func TestScanCmd_NoneFound(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+
+	output, err := executeCommand(t, "scan", "127.0.0.1", "--port", strconv.Itoa(closedPort(t)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "No SSH servers found") {
+		t.Errorf("Expected no servers, got:\n%s", output)
+	}
+}
+
+func TestScanCmd_InvalidNetwork(t *testing.T) {
+	setupTestServers(t, models.Servers{})
+
+	for _, args := range [][]string{
+		{"scan", "10.0.0.0/8", "--port", "22"},
+		{"scan", "10.0.0.0/24", "--port", "70000"},
+		{"scan", "10.0.0.0/24", "--port", "22", "--rate", "-1"},
+	} {
+		if _, err := executeCommand(t, args...); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
+
+func TestResolveHits_Limited(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	oldLookup := lookupAddr
+	defer func() { lookupAddr = oldLookup }()
+	lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return []string{"host-" + addr + "."}, nil
+	}
+
+	addrs, _ := probe.ExpandNetwork("10.0.0.0/28")
+	hits := make([]scanHit, len(addrs))
+	for i, addr := range addrs {
+		hits[i] = scanHit{Hit: probe.Hit{Addr: addr, Port: 22}}
+	}
+	limiter := probe.NewLimiter(2, 0)
+	defer limiter.Stop()
+	resolveHits(context.Background(), hits, limiter, time.Second)
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 lookups at once, got %d", peak)
+	}
+	for _, h := range hits {
+		if h.DNSName != "host-"+h.Addr.String() {
+			t.Errorf("Expected %s to be resolved, got %q", h.Addr, h.DNSName)
+		}
+	}
+}
+
+func TestResolveHits_NoTimeout(t *testing.T) {
+	oldLookup := lookupAddr
+	defer func() { lookupAddr = oldLookup }()
+	lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return []string{"host-" + addr + "."}, nil
+	}
+
+	hits := []scanHit{{Hit: probe.Hit{Addr: netip.MustParseAddr("10.0.0.1"), Port: 22}}}
+	limiter := probe.NewLimiter(1, 0)
+	defer limiter.Stop()
+	resolveHits(context.Background(), hits, limiter, 0)
+
+	if hits[0].DNSName != "host-10.0.0.1" {
+		t.Errorf("Expected a zero timeout not to cancel the lookup, got %q", hits[0].DNSName)
+	}
+}
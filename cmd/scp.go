@@ -2,19 +2,18 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/omisai-tech/sshy/internal/config"
 	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
 	"github.com/spf13/cobra"
 )
 
 var scpCmd = &cobra.Command{
 	Use:   "scp [ssh-flags...] <source> <destination>",
 	Short: "Copy files to/from SSH servers",
-	Long:  "Copy files between local machine and SSH servers using scp. Use server:path for remote paths. SSH flags can be passed through.",
+	Long:  "Copy files between local machine and SSH servers using scp. Use server:path for remote paths. SSH flags can be passed through. Use --dry-run or --print to show the command without running it.",
 	Args:  cobra.MinimumNArgs(0), // Allow any number of args, we'll parse them
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Handle help flag manually since we need to allow unknown flags
@@ -23,6 +22,8 @@ var scpCmd = &cobra.Command{
 				return cmd.Help()
 			}
 		}
+		args, mode := extractPrintFlags(args)
+		args = extractRefreshFlag(args)
 
 		if len(args) < 2 {
 			return fmt.Errorf("usage: sshy scp [ssh-flags...] <source> <destination>")
@@ -32,7 +33,7 @@ var scpCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("error loading config: %w", err)
 		}
-		servers, err := config.LoadServersWithPath(cfg.ConfigPath, cfg.GetServersSource())
+		servers, err := config.LoadServersFromConfig(cfg)
 		if err != nil {
 			return fmt.Errorf("error loading servers: %w", err)
 		}
@@ -48,71 +49,51 @@ var scpCmd = &cobra.Command{
 
 		var server1, server2 *models.Server
 		if serverName1 != "" {
-			for i, s := range servers {
-				if s.Name == serverName1 {
-					server1 = &servers[i]
-					break
-				}
-			}
-			if server1 == nil {
+			s, found := findServer(servers, serverName1)
+			if !found {
 				return fmt.Errorf("server %s not found", serverName1)
 			}
+			server1 = &s
 		}
 		if serverName2 != "" {
-			for i, s := range servers {
-				if s.Name == serverName2 {
-					server2 = &servers[i]
-					break
-				}
-			}
-			if server2 == nil {
+			s, found := findServer(servers, serverName2)
+			if !found {
 				return fmt.Errorf("server %s not found", serverName2)
 			}
+			server2 = &s
 		}
 
-		scpArgs := []string{}
-
-		var server *models.Server
+		// Key, port, options and jump hosts come from the first remote endpoint
+		var target sshcmd.Target
+		var remoteTarget *sshcmd.Target
 		if server1 != nil {
-			server = server1
+			target, err = sshcmd.NewTarget(*server1, servers)
+			remoteTarget = &target
 		} else if server2 != nil {
-			server = server2
-		}
-		if server != nil {
-			if server.Key != "" {
-				scpArgs = append(scpArgs, "-i", server.Key)
-			}
-			if server.Port != 0 && server.Port != 22 {
-				scpArgs = append(scpArgs, "-P", fmt.Sprintf("%d", server.Port))
-			}
+			target, err = sshcmd.NewTarget(*server2, servers)
+			remoteTarget = &target
 		}
-
-		filteredArgs := []string{}
-		for i := 0; i < len(sshArgs); i++ {
-			if sshArgs[i] == "-l" && i+1 < len(sshArgs) {
-				i++
-				continue
-			}
-			filteredArgs = append(filteredArgs, sshArgs[i])
+		if err != nil {
+			return err
 		}
-		scpArgs = append(scpArgs, filteredArgs...)
 
 		if server1 != nil {
-			scpArgs = append(scpArgs, buildScpTarget(*server1, remotePath1, sshArgs))
-		} else {
-			scpArgs = append(scpArgs, source)
+			source = buildScpTarget(*server1, remotePath1, sshArgs)
 		}
 		if server2 != nil {
-			scpArgs = append(scpArgs, buildScpTarget(*server2, remotePath2, sshArgs))
-		} else {
-			scpArgs = append(scpArgs, destination)
+			destination = buildScpTarget(*server2, remotePath2, sshArgs)
 		}
 
-		scpCmd := exec.Command("scp", scpArgs...)
-		scpCmd.Stdin = os.Stdin
-		scpCmd.Stdout = os.Stdout
-		scpCmd.Stderr = os.Stderr
-		err = scpCmd.Run()
+		inv, err := sshcmd.SCP(target, sshArgs, source, destination)
+		if err != nil {
+			return err
+		}
+
+		if mode != printNone {
+			return printInvocation(cmd.OutOrStdout(), mode, remoteTarget, inv)
+		}
+
+		err = cmdRunner.Run(inv.Program, inv.Args)
 		if err != nil {
 			return fmt.Errorf("error copying: %w", err)
 		}
@@ -129,20 +110,7 @@ func parsePath(path string) (serverName, remotePath string) {
 }
 
 func buildScpTarget(s models.Server, remotePath string, sshArgs []string) string {
-	user := s.User
-
-	for i, arg := range sshArgs {
-		if arg == "-l" && i+1 < len(sshArgs) {
-			user = sshArgs[i+1]
-			break
-		}
-	}
-
-	userHost := s.Host
-	if user != "" {
-		userHost = user + "@" + s.Host
-	}
-	return userHost + ":" + remotePath
+	return sshcmd.RemotePath(s, sshArgs, remotePath)
 }
 
 func init() {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/omisai-tech/sshy/internal/models"
@@ -125,3 +126,63 @@ func TestBuildScpTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestScpCmd_UsesCommandRunner(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "bastion", Host: "bastion.example.com", User: "ops"},
+		{Name: "web", Host: "web.internal", User: "deploy", Port: 2222, Key: "~/.ssh/web", Jump: models.JumpChain{"bastion"}},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	if err := scpCmd.RunE(scpCmd, []string{"-r", "-l", "root", "./dist", "web:/srv/app"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mock.LastCommand != "scp" {
+		t.Errorf("Expected command 'scp', got '%s'", mock.LastCommand)
+	}
+	expected := []string{
+		"-i", "~/.ssh/web", "-P", "2222",
+		"-o", "ProxyCommand=ssh -W %h:%p ops@bastion.example.com",
+		"-r", "./dist", "root@web.internal:/srv/app",
+	}
+	if strings.Join(mock.LastArgs, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, mock.LastArgs)
+	}
+}
+
+func TestScpCmd_UnknownServer(t *testing.T) {
+	setupTestServers(t, models.Servers{{Name: "web", Host: "web.internal"}})
+
+	err := scpCmd.RunE(scpCmd, []string{"./file", "nope:/tmp"})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("Expected server not found error, got %v", err)
+	}
+}
+
+func TestSftpCmd_UsesCommandRunner(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web", Host: "web.internal", User: "deploy", Port: 2222, Options: map[string]interface{}{"ServerAliveInterval": 15}},
+	})
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	if err := sftpCmd.RunE(sftpCmd, []string{"-C", "web"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mock.LastCommand != "sftp" {
+		t.Errorf("Expected command 'sftp', got '%s'", mock.LastCommand)
+	}
+	expected := []string{"-P", "2222", "-o", "ServerAliveInterval=15", "-C", "deploy@web.internal"}
+	if strings.Join(mock.LastArgs, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, mock.LastArgs)
+	}
+}
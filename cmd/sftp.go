@@ -2,18 +2,16 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 
 	"github.com/omisai-tech/sshy/internal/config"
-	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
 	"github.com/spf13/cobra"
 )
 
 var sftpCmd = &cobra.Command{
 	Use:   "sftp [ssh-flags...] <name>",
 	Short: "Start SFTP session with SSH server",
-	Long:  "Start an interactive SFTP session with the specified SSH server. SSH flags can be passed through.",
+	Long:  "Start an interactive SFTP session with the specified SSH server. SSH flags can be passed through. Use --dry-run or --print to show the command without running it.",
 	Args:  cobra.MinimumNArgs(0), // Allow any number of args, we'll parse them
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Handle help flag manually since we need to allow unknown flags
@@ -22,6 +20,8 @@ var sftpCmd = &cobra.Command{
 				return cmd.Help()
 			}
 		}
+		args, mode := extractPrintFlags(args)
+		args = extractRefreshFlag(args)
 
 		if len(args) < 1 {
 			return fmt.Errorf("usage: sshy sftp [ssh-flags...] <name>")
@@ -31,7 +31,7 @@ var sftpCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("error loading config: %w", err)
 		}
-		servers, err := config.LoadServersWithPath(cfg.ConfigPath, cfg.GetServersSource())
+		servers, err := config.LoadServersFromConfig(cfg)
 		if err != nil {
 			return fmt.Errorf("error loading servers: %w", err)
 		}
@@ -40,55 +40,25 @@ var sftpCmd = &cobra.Command{
 		name := args[len(args)-1]
 		sshArgs := args[:len(args)-1]
 
-		var selectedServer models.Server
-		for _, s := range servers {
-			if s.Name == name {
-				selectedServer = s
-				break
-			}
-		}
-		if selectedServer.Name == "" {
+		selectedServer, found := findServer(servers, name)
+		if !found {
 			return fmt.Errorf("server not found: %s", name)
 		}
 
-		sftpArgs := []string{}
-
-		if selectedServer.Key != "" {
-			sftpArgs = append(sftpArgs, "-i", selectedServer.Key)
-		}
-		if selectedServer.Port != 0 && selectedServer.Port != 22 {
-			sftpArgs = append(sftpArgs, "-P", fmt.Sprintf("%d", selectedServer.Port))
-		}
-
-		filteredArgs := []string{}
-		for i := 0; i < len(sshArgs); i++ {
-			if sshArgs[i] == "-l" && i+1 < len(sshArgs) {
-				i++
-				continue
-			}
-			filteredArgs = append(filteredArgs, sshArgs[i])
+		target, err := sshcmd.NewTarget(selectedServer, servers)
+		if err != nil {
+			return err
 		}
-		sftpArgs = append(sftpArgs, filteredArgs...)
-
-		user := selectedServer.User
-		for i, arg := range sshArgs {
-			if arg == "-l" && i+1 < len(sshArgs) {
-				user = sshArgs[i+1]
-				break
-			}
+		inv, err := sshcmd.SFTP(target, sshArgs)
+		if err != nil {
+			return err
 		}
 
-		userHost := selectedServer.Host
-		if user != "" {
-			userHost = user + "@" + selectedServer.Host
+		if mode != printNone {
+			return printInvocation(cmd.OutOrStdout(), mode, &target, inv)
 		}
-		sftpArgs = append(sftpArgs, userHost)
 
-		sftpCmd := exec.Command("sftp", sftpArgs...)
-		sftpCmd.Stdin = os.Stdin
-		sftpCmd.Stdout = os.Stdout
-		sftpCmd.Stderr = os.Stderr
-		err = sftpCmd.Run()
+		err = cmdRunner.Run(inv.Program, inv.Args)
 		if err != nil {
 			return fmt.Errorf("error starting SFTP: %w", err)
 		}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/omisai-tech/sshy/internal/sshconfig"
+	"github.com/spf13/cobra"
+)
+
+var sshConfigCmd = &cobra.Command{
+	Use:   "ssh-config",
+	Short: "Integrate sshy with the OpenSSH client config",
+}
+
+var sshConfigInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Let ssh reach sshy servers by name through \"sshy proxy\"",
+	Long: `Add a snippet to the managed block of ~/.ssh/config that routes every host
+name that is a server in the inventory through "sshy proxy". Servers are
+resolved when ssh connects, so "ssh NAME" keeps working as the inventory
+changes, including servers loaded from a URL, without exporting again.
+
+This replaces a block written by "sshy export ssh-config --managed-block".`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		output, _ := cmd.Flags().GetString("output")
+
+		executable, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		dir, err := config.Dir()
+		if err != nil {
+			return err
+		}
+		snippet := proxySnippet(executable, filepath.Join(dir, proxyConfigFile))
+		if dryRun {
+			fmt.Fprint(cmd.OutOrStdout(), snippet)
+			return nil
+		}
+
+		if output == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			output = filepath.Join(home, ".ssh", "config")
+		}
+		current, err := os.ReadFile(output)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		content, err := sshconfig.ReplaceBlock(string(current), snippet)
+		if err != nil {
+			return fmt.Errorf("%s: %w", output, err)
+		}
+		if content == string(current) {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", output)
+			return nil
+		}
+		if err := writeFileAtomic(output, []byte(content), 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Updated %s\n", output)
+		return nil
+	},
+}
+
+// proxySnippet routes names known to sshy through "sshy proxy". The Include
+// sits inside the Match block, so it is only read for sshy servers, and is
+// read after "--test" has refreshed it.
+func proxySnippet(executable, include string) string {
+	sshy := sshcmd.Quote(executable)
+	return sshconfig.BeginMarker + "\n" +
+		"# Installed by \"sshy ssh-config install\".\n" +
+		fmt.Sprintf("Match exec \"%s proxy --test %%n\"\n", sshy) +
+		fmt.Sprintf("  ProxyCommand %s proxy %%n\n", sshy) +
+		fmt.Sprintf("  Include \"%s\"\n", include) +
+		"Match all\n" +
+		sshconfig.EndMarker + "\n"
+}
+
+func init() {
+	rootCmd.AddCommand(sshConfigCmd)
+	sshConfigCmd.AddCommand(sshConfigInstallCmd)
+
+	sshConfigInstallCmd.Flags().Bool("dry-run", false, "Print the snippet instead of installing it")
+	sshConfigInstallCmd.Flags().StringP("output", "o", "", "Install into this file instead of ~/.ssh/config")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/spf13/cobra"
+)
+
+func loadInventory() (models.Servers, error) {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+	servers, err := config.LoadServersFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error loading servers: %w", err)
+	}
+	return servers, nil
+}
+
+// selectServers picks the servers named explicitly plus those carrying all of
+// the given tags, in inventory order. Unless all is set, at least one name or
+// tag is required so a command never fans out to the whole inventory by
+// accident.
+func selectServers(servers models.Servers, names, tags []string, all bool) (models.Servers, error) {
+	if len(names) == 0 && len(tags) == 0 && !all {
+		return nil, fmt.Errorf("no servers selected: pass server names, --tags or --all")
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, found := findServer(servers, name); !found {
+			return nil, fmt.Errorf("server not found: %s", name)
+		}
+		wanted[name] = true
+	}
+
+	var selected models.Servers
+	for _, s := range servers {
+		if all || wanted[s.Name] || (len(tags) > 0 && hasAllTags(s.Tags, tags)) {
+			selected = append(selected, s)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no servers match the selection")
+	}
+	return selected, nil
+}
+
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("tags", "t", []string{}, "Select servers having all of these tags (comma-separated)")
+	cmd.Flags().Bool("all", false, "Select every server in the inventory")
+}
+
+func selectionFromFlags(cmd *cobra.Command) ([]string, bool) {
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	all, _ := cmd.Flags().GetBool("all")
+	return tags, all
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestSelectServers(t *testing.T) {
+	servers := models.Servers{
+		{Name: "web-1", Tags: []string{"prod", "web"}},
+		{Name: "web-2", Tags: []string{"staging", "web"}},
+		{Name: "db-1", Tags: []string{"prod", "db"}},
+	}
+
+	tests := []struct {
+		name     string
+		names    []string
+		tags     []string
+		all      bool
+		expected []string
+		wantErr  string
+	}{
+		{name: "by tags", tags: []string{"web"}, expected: []string{"web-1", "web-2"}},
+		{name: "by all tags", tags: []string{"prod", "web"}, expected: []string{"web-1"}},
+		{name: "by names", names: []string{"db-1", "web-2"}, expected: []string{"web-2", "db-1"}},
+		{name: "names and tags", names: []string{"web-2"}, tags: []string{"db"}, expected: []string{"web-2", "db-1"}},
+		{name: "all", all: true, expected: []string{"web-1", "web-2", "db-1"}},
+		{name: "nothing", wantErr: "no servers selected"},
+		{name: "unknown name", names: []string{"web-3"}, wantErr: "server not found: web-3"},
+		{name: "no match", tags: []string{"cache"}, wantErr: "no servers match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectServers(servers, tt.names, tt.tags, tt.all)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var got []string
+			for _, s := range selected {
+				got = append(got, s.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/spf13/cobra"
+)
+
+type tmuxOptions struct {
+	Synchronize bool
+	Layout      string
+	// MaxPanes limits the panes per window; further servers go to new windows.
+	MaxPanes int
+	// InsideTmux opens windows in the current session instead of starting a
+	// new one.
+	InsideTmux bool
+	Session    string
+	WindowName string
+}
+
+type tmuxPane struct {
+	Title   string
+	Command string
+}
+
+var tmuxCmd = &cobra.Command{
+	Use:   "tmux [names...] [--tags tags]",
+	Short: "Open one tmux pane per server",
+	Long: `Open a tmux window with one pane per selected server, each running the ssh
+command that connect would use. Inside tmux a new window is opened in the
+current session; otherwise a new session is started and attached.
+
+Panes are titled with the server names and arranged with --layout. Windows
+hold at most --max-panes panes; further servers overflow into new windows.
+With --sync, keystrokes go to every pane of a window at once.`,
+	Example: `  sshy tmux --tags web
+  sshy tmux web-1 web-2 db-1 --sync
+  sshy tmux --tags prod --max-panes 6 --layout even-vertical`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, all := selectionFromFlags(cmd)
+		opts := tmuxOptions{InsideTmux: os.Getenv("TMUX") != "", WindowName: "sshy"}
+		opts.Synchronize, _ = cmd.Flags().GetBool("sync")
+		opts.Layout, _ = cmd.Flags().GetString("layout")
+		opts.MaxPanes, _ = cmd.Flags().GetInt("max-panes")
+		opts.Session, _ = cmd.Flags().GetString("session")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if opts.MaxPanes < 1 {
+			return fmt.Errorf("--max-panes must be at least 1")
+		}
+		if len(tags) > 0 {
+			opts.WindowName = tags[0]
+		}
+
+		inventory, err := loadInventory()
+		if err != nil {
+			return err
+		}
+		selected, err := selectServers(inventory, args, tags, all)
+		if err != nil {
+			return err
+		}
+
+		panes := make([]tmuxPane, len(selected))
+		for i, s := range selected {
+			target, err := sshcmd.NewTarget(s, inventory)
+			if err != nil {
+				return err
+			}
+			inv, err := sshcmd.SSH(target, nil, "")
+			if err != nil {
+				return err
+			}
+			panes[i] = tmuxPane{Title: s.Name, Command: inv.String()}
+		}
+
+		args = tmuxArgs(panes, opts)
+		if dryRun {
+			fmt.Fprintln(cmd.OutOrStdout(), sshcmd.Join(append([]string{"tmux"}, args...)))
+			return nil
+		}
+		if err := cmdRunner.Run("tmux", args); err != nil {
+			return fmt.Errorf("error running tmux: %w", err)
+		}
+		return nil
+	},
+}
+
+// tmuxArgs builds a single tmux invocation that creates the windows and
+// panes. The commands are separated by ";" arguments, so each one acts on the
+// window and pane created by the previous one.
+func tmuxArgs(panes []tmuxPane, opts tmuxOptions) []string {
+	var args []string
+	windows := 0
+	for start := 0; start < len(panes); start += opts.MaxPanes {
+		end := min(start+opts.MaxPanes, len(panes))
+		windows++
+		name := opts.WindowName
+		if len(panes) > opts.MaxPanes {
+			name = fmt.Sprintf("%s-%d", opts.WindowName, windows)
+		}
+
+		if len(args) > 0 {
+			args = append(args, ";")
+		}
+		if windows == 1 && !opts.InsideTmux {
+			args = append(args, "new-session")
+			if opts.Session != "" {
+				args = append(args, "-s", opts.Session)
+			}
+			args = append(args, "-n", name, panes[start].Command)
+		} else {
+			args = append(args, "new-window", "-n", name, panes[start].Command)
+		}
+		args = append(args, ";", "select-pane", "-T", panes[start].Title)
+
+		for _, pane := range panes[start+1 : end] {
+			args = append(args,
+				";", "split-window", pane.Command,
+				";", "select-pane", "-T", pane.Title,
+				// Re-tile after every split so that tmux has room for the next pane.
+				";", "select-layout", opts.Layout,
+			)
+		}
+		args = append(args,
+			";", "select-layout", opts.Layout,
+			";", "set-window-option", "pane-border-status", "top",
+			";", "set-window-option", "pane-border-format", " #{pane_title} ",
+		)
+		if opts.Synchronize {
+			args = append(args, ";", "set-window-option", "synchronize-panes", "on")
+		}
+	}
+	return args
+}
+
+func init() {
+	rootCmd.AddCommand(tmuxCmd)
+
+	addSelectionFlags(tmuxCmd)
+	tmuxCmd.Flags().Bool("sync", false, "Synchronize input across the panes of each window")
+	tmuxCmd.Flags().String("layout", "tiled", "tmux layout: tiled, even-horizontal, even-vertical, main-horizontal or main-vertical")
+	tmuxCmd.Flags().Int("max-panes", 9, "Maximum panes per window; more servers open further windows")
+	tmuxCmd.Flags().String("session", "", "Name of the session to create when not inside tmux")
+	tmuxCmd.Flags().Bool("dry-run", false, "Print the tmux command without running it")
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// tmuxCommands splits tmux arguments into the individual commands.
+func tmuxCommands(args []string) []string {
+	var commands []string
+	var current []string
+	for _, arg := range args {
+		if arg == ";" {
+			commands = append(commands, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, arg)
+	}
+	return append(commands, strings.Join(current, " "))
+}
+
+func TestTmuxArgs(t *testing.T) {
+	panes := []tmuxPane{
+		{Title: "web-1", Command: "ssh web-1"},
+		{Title: "web-2", Command: "ssh web-2"},
+		{Title: "web-3", Command: "ssh web-3"},
+	}
+	windowOptions := []string{
+		"select-layout tiled",
+		"set-window-option pane-border-status top",
+		"set-window-option pane-border-format  #{pane_title} ",
+	}
+
+	tests := []struct {
+		name     string
+		opts     tmuxOptions
+		expected []string
+	}{
+		{
+			name: "new session",
+			opts: tmuxOptions{Layout: "tiled", MaxPanes: 9, WindowName: "web"},
+			expected: append([]string{
+				"new-session -n web ssh web-1", "select-pane -T web-1",
+				"split-window ssh web-2", "select-pane -T web-2", "select-layout tiled",
+				"split-window ssh web-3", "select-pane -T web-3", "select-layout tiled",
+			}, windowOptions...),
+		},
+		{
+			name: "inside tmux with sync",
+			opts: tmuxOptions{Layout: "tiled", MaxPanes: 9, WindowName: "web", InsideTmux: true, Synchronize: true},
+			expected: append(append([]string{
+				"new-window -n web ssh web-1", "select-pane -T web-1",
+				"split-window ssh web-2", "select-pane -T web-2", "select-layout tiled",
+				"split-window ssh web-3", "select-pane -T web-3", "select-layout tiled",
+			}, windowOptions...), "set-window-option synchronize-panes on"),
+		},
+		{
+			name: "overflow into new windows",
+			opts: tmuxOptions{Layout: "tiled", MaxPanes: 2, WindowName: "web", Session: "ops"},
+			expected: append(append([]string{
+				"new-session -s ops -n web-1 ssh web-1", "select-pane -T web-1",
+				"split-window ssh web-2", "select-pane -T web-2", "select-layout tiled",
+			}, windowOptions...), append([]string{
+				"new-window -n web-2 ssh web-3", "select-pane -T web-3",
+			}, windowOptions...)...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tmuxCommands(tmuxArgs(panes, tt.opts))
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestTmuxCmd(t *testing.T) {
+	setupTestServers(t, models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"web"}},
+		{Name: "web-2", Host: "10.0.0.2", User: "deploy", Port: 2222, Tags: []string{"web"}},
+		{Name: "db-1", Host: "10.0.1.1", Tags: []string{"db"}},
+	})
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1234,0")
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	if _, err := executeCommand(t, "tmux", "--tags", "web", "--sync"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mock.LastCommand != "tmux" {
+		t.Fatalf("Expected tmux to be run, got %q", mock.LastCommand)
+	}
+	commands := tmuxCommands(mock.LastArgs)
+	for _, expected := range []string{
+		"new-window -n web ssh deploy@10.0.0.1",
+		"split-window ssh -p 2222 deploy@10.0.0.2",
+		"select-pane -T web-2",
+		"set-window-option synchronize-panes on",
+	} {
+		if !contains(commands, expected) {
+			t.Errorf("Expected tmux command %q, got:\n%s", expected, strings.Join(commands, "\n"))
+		}
+	}
+
+	out, err := executeCommand(t, "tmux", "db-1", "--dry-run")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "tmux new-window -n sshy 'ssh 10.0.1.1' ';' select-pane -T db-1") {
+		t.Errorf("Unexpected dry run output: %s", out)
+	}
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/spf13/cobra"
+)
+
+type transferDirection int
+
+const (
+	transferPush transferDirection = iota
+	transferPull
+)
+
+// transfer is one planned copy between the local machine and a server.
+type transfer struct {
+	Server      models.Server
+	Source      string
+	Destination string
+	// LocalDir is created before a pull copies into it.
+	LocalDir   string
+	Invocation sshcmd.Invocation
+}
+
+var pushCmd = &cobra.Command{
+	Use:   "push <local> (<names>:<remote> | --tags <tags>:<remote>)",
+	Short: "Copy a local file to many servers in parallel",
+	Long: `Copy a local file or directory to every selected server with scp, several
+servers at a time.
+
+Select servers either with a comma-separated list of names before the colon, or
+with --tags (servers having all of the tags). Both paths may contain {name},
+{host}, {user} and {tags}, which are replaced per server. A summary of
+successes and failures per server is printed at the end.`,
+	Example: `  sshy push ./app.conf --tags web:/etc/app/app.conf
+  sshy push ./configs/{name}.yaml web-1,web-2:/etc/app/config.yaml
+  sshy push -r ./static --tags prod,web:/srv/www/`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTransferCommand(cmd, transferPush, args)
+	},
+}
+
+var pullCmd = &cobra.Command{
+	Use:   "pull (<names>:<remote> | --tags <tags>:<remote>) <local>",
+	Short: "Copy a file from many servers in parallel",
+	Long: `Copy a remote file or directory from every selected server with scp, several
+servers at a time.
+
+Select servers either with a comma-separated list of names before the colon, or
+with --tags (servers having all of the tags). Both paths may contain {name},
+{host}, {user} and {tags}, which are replaced per server; the local path must
+contain one of them when pulling from more than one server, so that files do
+not overwrite each other. Missing local directories are created.`,
+	Example: `  sshy pull --tags web:/var/log/app.log ./logs/{name}/
+  sshy pull db-1,db-2:/etc/postgresql/16/main/postgresql.conf ./conf/{name}.conf`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTransferCommand(cmd, transferPull, args)
+	},
+}
+
+func runTransferCommand(cmd *cobra.Command, direction transferDirection, args []string) error {
+	tagSpec, _ := cmd.Flags().GetString("tags")
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	remoteSpec, local, err := splitTransferArgs(direction, args, tagSpec)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := loadInventory()
+	if err != nil {
+		return err
+	}
+	selector, remotePath := parsePath(remoteSpec)
+	if remotePath == "" {
+		return fmt.Errorf("missing remote path in %q: use <servers>:<path>", remoteSpec)
+	}
+	var selected models.Servers
+	if tagSpec != "" {
+		selected, err = selectServers(inventory, nil, splitList(selector), false)
+	} else {
+		selected, err = selectServers(inventory, splitList(selector), nil, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	var flags []string
+	if recursive {
+		flags = append(flags, "-r")
+	}
+	transfers, err := planTransfers(inventory, selected, direction, local, remotePath, flags)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		for _, t := range transfers {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", t.Server.Name, t.Invocation.String())
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := runTransfers(ctx, transfers, concurrency, timeout)
+	printResultsTable(cmd.OutOrStdout(), results)
+	printProgressReport(cmd.OutOrStdout(), results)
+	return resultsError(results)
+}
+
+// splitTransferArgs returns the "<servers>:<path>" spec and the local path.
+// With --tags the spec comes from the flag and only the local path is given.
+func splitTransferArgs(direction transferDirection, args []string, tagSpec string) (string, string, error) {
+	if tagSpec != "" {
+		if len(args) != 1 {
+			return "", "", fmt.Errorf("expected only a local path when --tags is given")
+		}
+		return tagSpec, args[0], nil
+	}
+	if len(args) != 2 {
+		return "", "", fmt.Errorf("expected a local path and <names>:<path>, or --tags <tags>:<path>")
+	}
+	if direction == transferPush {
+		return args[1], args[0], nil
+	}
+	return args[0], args[1], nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// planTransfers expands the path templates for every server and builds the
+// scp invocations. Pulls into the same local path from several servers are
+// rejected, as the copies would overwrite each other.
+func planTransfers(inventory, servers models.Servers, direction transferDirection, local, remote string, flags []string) ([]transfer, error) {
+	transfers := make([]transfer, 0, len(servers))
+	destinations := make(map[string]string)
+	for _, s := range servers {
+		target, err := sshcmd.NewTarget(s, inventory)
+		if err != nil {
+			return nil, err
+		}
+		localPath := expandPathTemplate(local, s)
+		remotePath := buildScpTarget(s, expandPathTemplate(remote, s), flags)
+
+		t := transfer{Server: s, Source: localPath, Destination: remotePath}
+		if direction == transferPull {
+			t.Source, t.Destination = remotePath, localPath
+			if other, dup := destinations[localPath]; dup {
+				return nil, fmt.Errorf("%s and %s would both be copied to %s: add {name} to the local path", other, s.Name, localPath)
+			}
+			destinations[localPath] = s.Name
+			t.LocalDir = filepath.Dir(localPath)
+			if strings.HasSuffix(localPath, "/") || strings.HasSuffix(localPath, string(filepath.Separator)) {
+				t.LocalDir = localPath
+			}
+		}
+		t.Invocation, err = sshcmd.SCP(target, flags, t.Source, t.Destination)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, nil
+}
+
+// expandPathTemplate replaces {name}, {host}, {user} and {tags} with the
+// server's values.
+func expandPathTemplate(path string, s models.Server) string {
+	return strings.NewReplacer(
+		"{name}", s.Name,
+		"{host}", s.Host,
+		"{user}", s.User,
+		"{tags}", strings.Join(s.Tags, ","),
+	).Replace(path)
+}
+
+func runTransfers(ctx context.Context, transfers []transfer, concurrency int, timeout time.Duration) []hostResult {
+	servers := make(models.Servers, len(transfers))
+	for i, t := range transfers {
+		servers[i] = t.Server
+	}
+
+	results := make([]hostResult, len(transfers))
+	forEachServer(ctx, servers, concurrency, func(ctx context.Context, i int, s models.Server) {
+		t := transfers[i]
+		result := hostResult{Name: s.Name, Host: s.Host}
+		start := time.Now()
+		if t.LocalDir != "" {
+			if err := os.MkdirAll(t.LocalDir, 0755); err != nil {
+				results[i] = failedResult(result, start, err)
+				return
+			}
+		}
+		var stderr bytes.Buffer
+		result = runInvocation(ctx, result, start, t.Invocation, timeout, io.Discard, &stderr)
+		if result.failed() && result.Error == "" {
+			result.Error = lastLine(stderr.String())
+		}
+		results[i] = result
+	})
+	for i, t := range transfers {
+		if results[i].Name == "" {
+			results[i] = hostResult{Name: t.Server.Name, Host: t.Server.Host, ExitCode: -1, Error: errNotStarted}
+		}
+	}
+	return results
+}
+
+func addTransferFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("tags", "t", "", "Servers having all of these tags, with the remote path: tags:path")
+	cmd.Flags().BoolP("recursive", "r", false, "Copy directories recursively")
+	cmd.Flags().IntP("concurrency", "c", 5, "Maximum number of transfers at once")
+	cmd.Flags().Duration("timeout", 0, "Per-server timeout, e.g. 5m (0 means no timeout)")
+	cmd.Flags().Bool("dry-run", false, "Print the scp command for each server without running it")
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+	rootCmd.AddCommand(pullCmd)
+
+	addTransferFlags(pushCmd)
+	addTransferFlags(pullCmd)
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func transferTestServers() models.Servers {
+	return models.Servers{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"prod", "web"}},
+		{Name: "web-2", Host: "10.0.0.2", User: "deploy", Port: 2222, Tags: []string{"prod", "web"}},
+		{Name: "db-1", Host: "10.0.1.1", User: "admin", Tags: []string{"prod", "db"}},
+	}
+}
+
+func TestExpandPathTemplate(t *testing.T) {
+	server := models.Server{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"prod", "web"}}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"./logs/{name}/", "./logs/web-1/"},
+		{"/srv/{host}/{user}.log", "/srv/10.0.0.1/deploy.log"},
+		{"{tags}-{name}.tar", "prod,web-web-1.tar"},
+		{"/etc/app.conf", "/etc/app.conf"},
+	}
+
+	for _, tt := range tests {
+		if got := expandPathTemplate(tt.path, server); got != tt.expected {
+			t.Errorf("expandPathTemplate(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestPlanTransfers(t *testing.T) {
+	servers := transferTestServers()[:2]
+
+	transfers, err := planTransfers(servers, servers, transferPull, "./logs/{name}/", "/var/log/app.log", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfers[0].Source != "deploy@10.0.0.1:/var/log/app.log" || transfers[0].Destination != "./logs/web-1/" || transfers[0].LocalDir != "./logs/web-1/" {
+		t.Errorf("Unexpected pull from web-1: %+v", transfers[0])
+	}
+	if !containsSequence(transfers[1].Invocation.Args, "-P", "2222") {
+		t.Errorf("Expected the server port in the scp args, got %v", transfers[1].Invocation.Args)
+	}
+
+	transfers, err = planTransfers(servers, servers, transferPush, "./app-{name}.conf", "/etc/app.conf", []string{"-r"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfers[1].Source != "./app-web-2.conf" || transfers[1].Destination != "deploy@10.0.0.2:/etc/app.conf" || transfers[1].LocalDir != "" {
+		t.Errorf("Unexpected push to web-2: %+v", transfers[1])
+	}
+	if !contains(transfers[0].Invocation.Args, "-r") {
+		t.Errorf("Expected -r in the scp args, got %v", transfers[0].Invocation.Args)
+	}
+
+	if _, err := planTransfers(servers, servers, transferPull, "./app.log", "/var/log/app.log", nil); err == nil || !strings.Contains(err.Error(), "{name}") {
+		t.Errorf("Expected error for pulls overwriting each other, got %v", err)
+	}
+}
+
+func TestPushCmd(t *testing.T) {
+	setupTestServers(t, transferTestServers())
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{
+		RunFunc: func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			if args[len(args)-1] == "deploy@10.0.0.2:/etc/app/app.conf" {
+				fmt.Fprintln(stderr, "scp: /etc/app/app.conf: Permission denied")
+				return mockExitError(1)
+			}
+			return nil
+		},
+	}
+	cmdRunner = mock
+
+	out, err := executeCommand(t, "push", "./app.conf", "--tags", "web:/etc/app/app.conf")
+	if err == nil || err.Error() != "failed on 1 of 2 servers" {
+		t.Errorf("Expected one failed server, got %v", err)
+	}
+	if !strings.Contains(out, "scp: /etc/app/app.conf: Permission denied") || !strings.Contains(out, "1 succeeded, 1 failed") {
+		t.Errorf("Expected per-server summary, got:\n%s", out)
+	}
+	if len(mock.Calls) != 2 {
+		t.Fatalf("Expected 2 scp calls, got %v", mock.Calls)
+	}
+	for _, call := range mock.Calls {
+		if call[0] != "scp" || call[len(call)-2] != "./app.conf" {
+			t.Errorf("Unexpected call: %v", call)
+		}
+	}
+}
+
+func TestPullCmd(t *testing.T) {
+	setupTestServers(t, transferTestServers())
+	dest := filepath.Join(t.TempDir(), "logs")
+
+	oldRunner := cmdRunner
+	defer func() { cmdRunner = oldRunner }()
+	mock := &MockCommandRunner{}
+	cmdRunner = mock
+
+	out, err := executeCommand(t, "pull", "web-1,db-1:/var/log/app.log", dest+"/{name}/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n%s", err, out)
+	}
+	for _, name := range []string{"web-1", "db-1"} {
+		if info, err := os.Stat(filepath.Join(dest, name)); err != nil || !info.IsDir() {
+			t.Errorf("Expected local directory for %s: %v", name, err)
+		}
+	}
+	sources := map[string]bool{}
+	for _, call := range mock.Calls {
+		sources[call[len(call)-2]] = true
+	}
+	if len(mock.Calls) != 2 || !sources["admin@10.0.1.1:/var/log/app.log"] || !sources["deploy@10.0.0.1:/var/log/app.log"] {
+		t.Errorf("Unexpected calls: %v", mock.Calls)
+	}
+
+	mock.Calls = nil
+	out, err = executeCommand(t, "pull", "--tags", "web:/var/log/app.log", dest+"/{name}/", "--dry-run")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mock.Calls) != 0 || !strings.Contains(out, "web-2: scp -P 2222 deploy@10.0.0.2:/var/log/app.log") {
+		t.Errorf("Expected dry run to print commands only, got:\n%s", out)
+	}
+}
+
+func TestTransferCmd_InvalidArgs(t *testing.T) {
+	setupTestServers(t, transferTestServers())
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing remote", []string{"push", "./app.conf"}},
+		{"missing path", []string{"push", "./app.conf", "web-1"}},
+		{"unknown server", []string{"push", "./app.conf", "web-9:/tmp/"}},
+		{"tags and remote", []string{"pull", "--tags", "web:/tmp/a", "web-1:/tmp/a", "./a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := executeCommand(t, tt.args...); err == nil {
+				t.Errorf("Expected error for %v", tt.args)
+			}
+		})
+	}
+}
@@ -2,8 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 
 	"github.com/ktr0731/go-fuzzyfinder"
@@ -50,19 +48,9 @@ You can view:
 		case 0:
 			if cfg.IsRemoteSource() {
 				title = fmt.Sprintf("Shared Configuration (URL: %s)", cfg.ServersURL)
-				resp, err := http.Get(cfg.ServersURL)
+				data, err = config.FetchURL(cfg.ServersURL, cfg.ServersAuth)
 				if err != nil {
-					fmt.Printf("Error fetching from URL: %v\n", err)
-					return
-				}
-				defer resp.Body.Close()
-				if resp.StatusCode != http.StatusOK {
-					fmt.Printf("Error: server returned status %d\n", resp.StatusCode)
-					return
-				}
-				data, err = io.ReadAll(resp.Body)
-				if err != nil {
-					fmt.Printf("Error reading response: %v\n", err)
+					fmt.Printf("Error: %v\n", err)
 					return
 				}
 			} else {
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Secret says where a credential is read from, so that it is never written
+// in the config itself. Exactly one field is set.
+type Secret struct {
+	// Env names an environment variable.
+	Env string `yaml:"env,omitempty" json:"env,omitempty"`
+	// File is a file whose contents, without the trailing newline, are the
+	// secret. A leading ~/ refers to the home directory.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+	// Command is run by the shell and its standard output, without the
+	// trailing newline, is the secret, as with "pass show inventory".
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+}
+
+// BasicAuth sends an Authorization: Basic header.
+type BasicAuth struct {
+	Username string `yaml:"username" json:"username"`
+	Password Secret `yaml:"password" json:"password"`
+}
+
+// Auth holds the credentials sent with the requests for a servers URL.
+type Auth struct {
+	// Bearer sends an Authorization: Bearer header.
+	Bearer *Secret    `yaml:"bearer,omitempty" json:"bearer,omitempty"`
+	Basic  *BasicAuth `yaml:"basic,omitempty" json:"basic,omitempty"`
+	// Headers are sent as they are, with their values read like secrets.
+	Headers map[string]Secret `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// secretCommand builds the command that runs a Secret's helper.
+var secretCommand = func(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+func (s Secret) validate() error {
+	set := 0
+	for _, v := range []string{s.Env, s.File, s.Command} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of env, file and command must be set")
+	}
+	return nil
+}
+
+// Resolve reads the secret.
+func (s Secret) Resolve() (string, error) {
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+	switch {
+	case s.Env != "":
+		value, ok := os.LookupEnv(s.Env)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", s.Env)
+		}
+		return value, nil
+	case s.File != "":
+		path := s.File
+		if strings.HasPrefix(path, "~/") {
+			home, err := userHomeDir()
+			if err != nil {
+				return "", err
+			}
+			path = filepath.Join(home, path[2:])
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		cmd := secretCommand(s.Command)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("command %q failed: %w: %s", s.Command, err, msg)
+			}
+			return "", fmt.Errorf("command %q failed: %w", s.Command, err)
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	}
+}
+
+// Validate checks that each secret names exactly one place to read it from,
+// without reading it.
+func (a *Auth) Validate() error {
+	if a == nil {
+		return nil
+	}
+	if a.Bearer != nil && a.Basic != nil {
+		return fmt.Errorf("auth: bearer and basic cannot be used together")
+	}
+	if a.Bearer != nil {
+		if err := a.Bearer.validate(); err != nil {
+			return fmt.Errorf("auth: bearer: %w", err)
+		}
+	}
+	if a.Basic != nil {
+		if a.Basic.Username == "" {
+			return fmt.Errorf("auth: basic: username is required")
+		}
+		if err := a.Basic.Password.validate(); err != nil {
+			return fmt.Errorf("auth: basic: password: %w", err)
+		}
+	}
+	for name, value := range a.Headers {
+		if err := value.validate(); err != nil {
+			return fmt.Errorf("auth: header %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// authHeadersKey marks a request with the names of the headers apply set,
+// so that dropRedirectAuth can remove them.
+type authHeadersKey struct{}
+
+// apply resolves the secrets of a and returns req with them set. Credentials
+// are only sent over plain http to the local machine.
+func (a *Auth) apply(req *http.Request) (*http.Request, error) {
+	if a == nil {
+		return req, nil
+	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	if req.URL.Scheme != "https" && !isLoopback(req.URL.Hostname()) {
+		return nil, fmt.Errorf("auth: refusing to send credentials to %s over plain http; use https", req.URL.Host)
+	}
+	var names []string
+	for name, secret := range a.Headers {
+		value, err := secret.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("auth: header %s: %w", name, err)
+		}
+		req.Header.Set(name, value)
+		names = append(names, name)
+	}
+	if a.Bearer != nil {
+		token, err := a.Bearer.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("auth: bearer: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		names = append(names, "Authorization")
+	}
+	if a.Basic != nil {
+		password, err := a.Basic.Password.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("auth: basic: password: %w", err)
+		}
+		req.SetBasicAuth(a.Basic.Username, password)
+		names = append(names, "Authorization")
+	}
+	return req.WithContext(context.WithValue(req.Context(), authHeadersKey{}, names)), nil
+}
+
+// dropRedirectAuth is the CheckRedirect of httpClient. net/http keeps custom
+// headers on every redirect, and Authorization on a downgrade to http, so
+// the headers set by apply are removed whenever the host or scheme changes.
+func dropRedirectAuth(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	names, _ := req.Context().Value(authHeadersKey{}).([]string)
+	if req.URL.Host != via[0].URL.Host || req.URL.Scheme != via[0].URL.Scheme {
+		for _, name := range names {
+			req.Header.Del(name)
+		}
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
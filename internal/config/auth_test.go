@@ -0,0 +1,176 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecret_Resolve(t *testing.T) {
+	t.Setenv("SSHY_TEST_TOKEN", "from-env")
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		secret  Secret
+		want    string
+		wantErr string
+	}{
+		{"env", Secret{Env: "SSHY_TEST_TOKEN"}, "from-env", ""},
+		{"file", Secret{File: file}, "from-file", ""},
+		{"command", Secret{Command: "echo from-command"}, "from-command", ""},
+		{"unset env", Secret{Env: "SSHY_TEST_UNSET"}, "", "is not set"},
+		{"missing file", Secret{File: file + ".missing"}, "", "no such file"},
+		{"failing command", Secret{Command: "echo oops >&2; exit 3"}, "", "oops"},
+		{"nothing set", Secret{}, "", "exactly one"},
+		{"two set", Secret{Env: "SSHY_TEST_TOKEN", File: file}, "", "exactly one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.secret.Resolve()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAuth_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *Auth
+		wantErr string
+	}{
+		{"nil", nil, ""},
+		{"bearer", &Auth{Bearer: &Secret{Env: "TOKEN"}}, ""},
+		{"bearer and basic", &Auth{Bearer: &Secret{Env: "TOKEN"}, Basic: &BasicAuth{Username: "u", Password: Secret{Env: "P"}}}, "cannot be used together"},
+		{"basic without username", &Auth{Basic: &BasicAuth{Password: Secret{Env: "P"}}}, "username is required"},
+		{"header without source", &Auth{Headers: map[string]Secret{"X-Team": {}}}, "header X-Team"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGlobalConfig_InlineSecretRejected(t *testing.T) {
+	var cfg GlobalConfig
+	data := "servers_url: https://example.com/servers.yaml\nservers_auth:\n  bearer: s3cret\n"
+	if err := yaml.Unmarshal([]byte(data), &cfg); err == nil {
+		t.Error("Expected an inline bearer token to be rejected")
+	}
+}
+
+func TestFetchServers_Auth(t *testing.T) {
+	setupURLCache(t)
+	t.Setenv("SSHY_TEST_TOKEN", "t0ken")
+	t.Setenv("SSHY_TEST_TEAM", "platform")
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte("- name: web-1\n  host: 10.0.0.1\n"))
+	}))
+	defer server.Close()
+
+	bearer := &Auth{
+		Bearer:  &Secret{Env: "SSHY_TEST_TOKEN"},
+		Headers: map[string]Secret{"X-Team": {Env: "SSHY_TEST_TEAM"}},
+	}
+	if _, err := fetchServers(server.URL, bearer, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Get("Authorization") != "Bearer t0ken" || got.Get("X-Team") != "platform" {
+		t.Errorf("Expected bearer and team headers, got %v", got)
+	}
+
+	basic := &Auth{Basic: &BasicAuth{Username: "deploy", Password: Secret{Command: "echo pa55"}}}
+	if _, err := FetchURL(server.URL, basic); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Get("Authorization") != "Basic ZGVwbG95OnBhNTU=" {
+		t.Errorf("Expected basic auth for deploy:pa55, got %q", got.Get("Authorization"))
+	}
+}
+
+func TestFetchURL_MissingSecret(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	_, err := FetchURL(server.URL, &Auth{Bearer: &Secret{Env: "SSHY_TEST_UNSET"}})
+	if err == nil || !strings.Contains(err.Error(), "auth: bearer") {
+		t.Errorf("Expected a bearer error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request without the secret, got %d", requests)
+	}
+}
+
+func TestFetchURL_PlainHTTP(t *testing.T) {
+	t.Setenv("SSHY_TEST_TOKEN", "t0ken")
+	_, err := FetchURL("http://inventory.example.com/servers.yaml", &Auth{Bearer: &Secret{Env: "SSHY_TEST_TOKEN"}})
+	if err == nil || !strings.Contains(err.Error(), "over plain http") {
+		t.Errorf("Expected credentials over plain http to be refused, got %v", err)
+	}
+}
+
+func TestFetchURL_RedirectDropsAuth(t *testing.T) {
+	t.Setenv("SSHY_TEST_TOKEN", "t0ken")
+	t.Setenv("SSHY_TEST_KEY", "k3y")
+
+	var got http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte("[]"))
+	}))
+	defer target.Close()
+	// Another host name for the same listener.
+	targetURL := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, targetURL+"/servers.yaml", http.StatusFound)
+	}))
+	defer origin.Close()
+
+	auth := &Auth{
+		Bearer:  &Secret{Env: "SSHY_TEST_TOKEN"},
+		Headers: map[string]Secret{"X-Api-Key": {Env: "SSHY_TEST_KEY"}},
+	}
+	if _, err := FetchURL(origin.URL, auth); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Get("Authorization") != "" || got.Get("X-Api-Key") != "" {
+		t.Errorf("Expected the credentials to be dropped on a cross-host redirect, got %v", got)
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// urlCacheDir holds the last good response of every remote servers list, so
+// that sshy keeps working when the URL cannot be reached.
+var urlCacheDir = func() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "urls"), nil
+}
+
+// cachedResponse is a remote servers list as last received, with the
+// validators needed for a conditional request.
+type cachedResponse struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	// FetchedAt is when the server last confirmed the body, either by
+	// sending it or by answering 304 Not Modified.
+	FetchedAt time.Time `json:"fetched_at"`
+	Body      string    `json:"body"`
+}
+
+func cachePath(urlStr string) (string, error) {
+	dir, err := urlCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(urlStr))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// loadCachedResponse returns nil if nothing usable is cached for urlStr.
+func loadCachedResponse(urlStr string) *cachedResponse {
+	path, err := cachePath(urlStr)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil || cached.URL != urlStr {
+		return nil
+	}
+	return &cached
+}
+
+func saveCachedResponse(cached *cachedResponse) error {
+	path, err := cachePath(cached.URL)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the URL tests from writing to the real ~/.sshy.
+	dir, err := os.MkdirTemp("", "sshy-url-cache-*")
+	if err != nil {
+		panic(err)
+	}
+	urlCacheDir = func() (string, error) { return dir, nil }
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func setupURLCache(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	dir := t.TempDir()
+	oldDir, oldWarn := urlCacheDir, warnOutput
+	var warnings bytes.Buffer
+	urlCacheDir = func() (string, error) { return dir, nil }
+	warnOutput = &warnings
+	t.Cleanup(func() {
+		urlCacheDir, warnOutput = oldDir, oldWarn
+		ForceRefresh, Offline = false, false
+	})
+	return &warnings
+}
+
+func TestFetchServers_ConditionalRequest(t *testing.T) {
+	setupURLCache(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 12 Oct 2026 08:00:00 GMT" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 12 Oct 2026 08:00:00 GMT")
+		w.Write([]byte("- name: web-1\n  host: 10.0.0.1\n"))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		servers, err := fetchServers(server.URL, nil, 0)
+		if err != nil {
+			t.Fatalf("Fetch %d: unexpected error: %v", i+1, err)
+		}
+		if len(servers) != 1 || servers[0].Host != "10.0.0.1" {
+			t.Errorf("Fetch %d: expected web-1, got %+v", i+1, servers)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestFetchServers_StaleFallback(t *testing.T) {
+	warnings := setupURLCache(t)
+	up := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("- name: web-1\n  host: 10.0.0.1\n"))
+	}))
+
+	if _, err := fetchServers(server.URL, nil, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	up = false
+	servers, err := fetchServers(server.URL, nil, 0)
+	if err != nil {
+		t.Fatalf("Expected the cached copy, got error: %v", err)
+	}
+	if len(servers) != 1 || servers[0].Name != "web-1" {
+		t.Errorf("Expected web-1 from the cache, got %+v", servers)
+	}
+	if !strings.Contains(warnings.String(), "status 502") || !strings.Contains(warnings.String(), "stale since") {
+		t.Errorf("Expected a stale warning, got %q", warnings.String())
+	}
+
+	server.Close()
+	warnings.Reset()
+	if _, err := fetchServers(server.URL, nil, 0); err != nil {
+		t.Fatalf("Expected the cached copy when offline, got error: %v", err)
+	}
+	if !strings.Contains(warnings.String(), "failed to fetch from URL") {
+		t.Errorf("Expected an offline warning, got %q", warnings.String())
+	}
+}
+
+func TestFetchServers_TTL(t *testing.T) {
+	setupURLCache(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("- name: web-1\n  host: 10.0.0.1\n"))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := fetchServers(server.URL, nil, time.Hour); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the cache to be used within the TTL, got %d requests", requests)
+	}
+
+	ForceRefresh = true
+	if _, err := fetchServers(server.URL, nil, time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected ForceRefresh to fetch, got %d requests", requests)
+	}
+}
+
+func TestFetchServers_Offline(t *testing.T) {
+	warnings := setupURLCache(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("- name: web-1\n  host: 10.0.0.1\n"))
+	}))
+	defer server.Close()
+
+	Offline = true
+	if servers, err := fetchServers(server.URL, nil, 0); err != nil || len(servers) != 0 {
+		t.Fatalf("Expected an empty list before anything is cached, got %v, %v", servers, err)
+	}
+	Offline = false
+	fetchServers(server.URL, nil, 0)
+
+	Offline, ForceRefresh = true, true
+	servers, err := fetchServers(server.URL, nil, 0)
+	if err != nil || len(servers) != 1 {
+		t.Fatalf("Expected the cached list, got %v, %v", servers, err)
+	}
+	if requests != 1 || warnings.Len() != 0 {
+		t.Errorf("Expected no fetch and no warning while offline, got %d requests and %q", requests, warnings.String())
+	}
+}
+
+func TestFetchServers_BadResponseNotCached(t *testing.T) {
+	setupURLCache(t)
+	body := "- name: web-1\n  host: 10.0.0.1\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	if _, err := fetchServers(server.URL, nil, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body = "not: [valid"
+	if _, err := fetchServers(server.URL, nil, 0); err != nil {
+		t.Fatalf("Expected the cached copy for an unparsable response, got error: %v", err)
+	}
+	if cached := loadCachedResponse(server.URL); cached == nil || !strings.Contains(cached.Body, "web-1") {
+		t.Errorf("Expected the last good response to stay cached, got %+v", cached)
+	}
+}
+
+func TestFetchServers_NoCacheError(t *testing.T) {
+	setupURLCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if _, err := fetchServers(server.URL, nil, time.Hour); err == nil {
+		t.Error("Expected an error without a cached copy")
+	}
+}
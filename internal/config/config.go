@@ -16,6 +16,11 @@ const (
 type LocalConfig struct {
 	Servers map[string]models.Server `yaml:"servers" json:"servers"`
 	Private models.Servers           `yaml:"private" json:"private"`
+	// Generated records the servers written by each synced import, keyed by
+	// import source, as a map from the name in the source to the name in the
+	// inventory, which differs when the import renamed it. A re-run uses it to
+	// find its servers again and to remove the ones whose origin is gone.
+	Generated map[string]map[string]string `yaml:"generated,omitempty" json:"generated,omitempty"`
 }
 
 func findConfigFile(basePath, primaryFile string) (string, FileFormat) {
@@ -76,30 +81,41 @@ func loadLocalConfig() (LocalConfig, error) {
 	return config, err
 }
 
+// ApplyOverride returns server with the fields set in override. Fields that
+// override leaves empty keep the value of server, so an override cannot
+// clear them.
+func ApplyOverride(server, override models.Server) models.Server {
+	if override.Host != "" {
+		server.Host = override.Host
+	}
+	if override.User != "" {
+		server.User = override.User
+	}
+	if override.Port != 0 {
+		server.Port = override.Port
+	}
+	if len(override.Tags) > 0 {
+		server.Tags = override.Tags
+	}
+	if override.Key != "" {
+		server.Key = override.Key
+	}
+	if len(override.Jump) > 0 {
+		server.Jump = override.Jump
+	}
+	if override.Options != nil {
+		server.Options = override.Options
+	}
+	return server
+}
+
 func mergeServers(sharedServers models.Servers, localConfig LocalConfig) models.Servers {
 	mergedServers := make(models.Servers, 0, len(sharedServers)+len(localConfig.Private))
 
 	for i := range sharedServers {
 		server := sharedServers[i]
 		if override, ok := localConfig.Servers[server.Name]; ok {
-			if override.Host != "" {
-				server.Host = override.Host
-			}
-			if override.User != "" {
-				server.User = override.User
-			}
-			if override.Port != 0 {
-				server.Port = override.Port
-			}
-			if len(override.Tags) > 0 {
-				server.Tags = override.Tags
-			}
-			if override.Key != "" {
-				server.Key = override.Key
-			}
-			if override.Options != nil {
-				server.Options = override.Options
-			}
+			server = ApplyOverride(server, override)
 		}
 		mergedServers = append(mergedServers, server)
 	}
@@ -131,6 +147,22 @@ func LoadServersWithPath(configPath, serversPath string) (models.Servers, error)
 		return LoadServersWithURL(serversPath)
 	}
 
+	sharedServers, err := LoadSharedServers(configPath, serversPath)
+	if err != nil {
+		return nil, err
+	}
+
+	localConfig, err := loadLocalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeServers(sharedServers, localConfig), nil
+}
+
+// LoadSharedServers reads only the shared servers file, without local
+// overrides or private servers. A missing file yields no servers.
+func LoadSharedServers(configPath, serversPath string) (models.Servers, error) {
 	sharedPath, format := findConfigFile(configPath, serversPath)
 	sharedData, err := os.ReadFile(sharedPath)
 	if err != nil {
@@ -151,13 +183,7 @@ func LoadServersWithPath(configPath, serversPath string) (models.Servers, error)
 			return nil, err
 		}
 	}
-
-	localConfig, err := loadLocalConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	return mergeServers(sharedServers, localConfig), nil
+	return sharedServers, nil
 }
 
 func SaveServers(configPath string, servers models.Servers) error {
@@ -233,32 +259,25 @@ func LoadServersWithSource(configPath string) ([]models.ServerWithSource, error)
 }
 
 func mergeServersWithSource(sharedServers models.Servers, localConfig LocalConfig) []models.ServerWithSource {
+	shared := make([]models.ServerWithSource, len(sharedServers))
+	for i, server := range sharedServers {
+		shared[i] = models.ServerWithSource{Server: server, Source: models.SourceShared}
+	}
+	return mergeSources(shared, localConfig)
+}
+
+// mergeSources applies the local overrides to shared servers, keeping their
+// origin, and appends the private servers.
+func mergeSources(sharedServers []models.ServerWithSource, localConfig LocalConfig) []models.ServerWithSource {
 	mergedServers := make([]models.ServerWithSource, 0, len(sharedServers)+len(localConfig.Private))
 
 	for i := range sharedServers {
-		server := sharedServers[i]
+		server, origin := sharedServers[i].Server, sharedServers[i].Origin
 		if override, ok := localConfig.Servers[server.Name]; ok {
-			if override.Host != "" {
-				server.Host = override.Host
-			}
-			if override.User != "" {
-				server.User = override.User
-			}
-			if override.Port != 0 {
-				server.Port = override.Port
-			}
-			if len(override.Tags) > 0 {
-				server.Tags = override.Tags
-			}
-			if override.Key != "" {
-				server.Key = override.Key
-			}
-			if override.Options != nil {
-				server.Options = override.Options
-			}
-			mergedServers = append(mergedServers, models.ServerWithSource{Server: server, Source: models.SourceOverride})
+			server = ApplyOverride(server, override)
+			mergedServers = append(mergedServers, models.ServerWithSource{Server: server, Source: models.SourceOverride, Origin: origin})
 		} else {
-			mergedServers = append(mergedServers, models.ServerWithSource{Server: server, Source: models.SourceShared})
+			mergedServers = append(mergedServers, models.ServerWithSource{Server: server, Source: models.SourceShared, Origin: origin})
 		}
 	}
 
@@ -270,43 +289,20 @@ func mergeServersWithSource(sharedServers models.Servers, localConfig LocalConfi
 }
 
 func LoadServersWithSourceURL(serversURL string) ([]models.ServerWithSource, error) {
-	sharedServers, err := FetchServersFromURL(serversURL)
-	if err != nil {
-		return nil, err
-	}
-
-	localConfig, err := loadLocalConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	return mergeServersWithSource(sharedServers, localConfig), nil
+	return LoadServersWithSourceAndPath("", serversURL)
 }
 
+// LoadServersWithSourceAndPath loads a single servers file or URL merged with
+// the local config. LoadServersWithSourceFromConfig also reads the
+// additional sources of the global config.
 func LoadServersWithSourceAndPath(configPath, serversPath string) ([]models.ServerWithSource, error) {
+	source := Source{Path: serversPath}
 	if IsURL(serversPath) {
-		return LoadServersWithSourceURL(serversPath)
+		source = Source{URL: serversPath}
 	}
-
-	sharedPath, format := findConfigFile(configPath, serversPath)
-	sharedData, err := os.ReadFile(sharedPath)
+	sharedServers, _, err := loadShared(configPath, 0, []Source{source})
 	if err != nil {
-		if os.IsNotExist(err) {
-			sharedData = []byte{}
-		} else {
-			return nil, err
-		}
-	}
-
-	if format == FormatUnknown && len(sharedData) > 0 {
-		format = DetectFormatFromContent(sharedData)
-	}
-
-	var sharedServers models.Servers
-	if len(sharedData) > 0 {
-		if err := Unmarshal(sharedData, format, &sharedServers); err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	localConfig, err := loadLocalConfig()
@@ -314,5 +310,5 @@ func LoadServersWithSourceAndPath(configPath, serversPath string) ([]models.Serv
 		return nil, err
 	}
 
-	return mergeServersWithSource(sharedServers, localConfig), nil
+	return mergeSources(sharedServers, localConfig), nil
 }
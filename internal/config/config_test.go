@@ -813,3 +813,28 @@ func TestSaveServers_JSONFormat(t *testing.T) {
 		t.Errorf("Saved servers don't match expected: %+v", savedServers)
 	}
 }
+
+func TestMergeServers_JumpOverride(t *testing.T) {
+	shared := models.Servers{
+		{Name: "db", Host: "db.internal", Jump: models.JumpChain{"old-bastion"}},
+		{Name: "web", Host: "web.internal", Jump: models.JumpChain{"bastion"}},
+	}
+	localConfig := LocalConfig{
+		Servers: map[string]models.Server{
+			"db":  {Jump: models.JumpChain{"new-bastion"}},
+			"web": {User: "deploy"},
+		},
+	}
+
+	for _, servers := range []models.Servers{
+		mergeServers(shared, localConfig),
+		{mergeServersWithSource(shared, localConfig)[0].Server, mergeServersWithSource(shared, localConfig)[1].Server},
+	} {
+		if len(servers[0].Jump) != 1 || servers[0].Jump[0] != "new-bastion" {
+			t.Errorf("Expected jump override 'new-bastion', got %v", servers[0].Jump)
+		}
+		if len(servers[1].Jump) != 1 || servers[1].Jump[0] != "bastion" {
+			t.Errorf("Expected jump 'bastion' to be kept, got %v", servers[1].Jump)
+		}
+	}
+}
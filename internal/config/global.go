@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/omisai-tech/sshy/internal/models"
 )
@@ -15,6 +17,14 @@ type GlobalConfig struct {
 	ServersPath string `yaml:"servers_path" json:"servers_path"`
 	ServersURL  string `yaml:"servers_url,omitempty" json:"servers_url,omitempty"`
 	ConfigPath  string `yaml:"config_path" json:"config_path"`
+	// ServersAuth holds the credentials sent to ServersURL.
+	ServersAuth *Auth `yaml:"servers_auth,omitempty" json:"servers_auth,omitempty"`
+	// Sources lists shared servers files and URLs read in addition to
+	// ServersPath or ServersURL.
+	Sources []Source `yaml:"sources,omitempty" json:"sources,omitempty"`
+	// URLCacheTTL is a duration, such as "10m", during which the cached copy
+	// of a remote servers list is used without asking the server.
+	URLCacheTTL string `yaml:"url_cache_ttl,omitempty" json:"url_cache_ttl,omitempty"`
 }
 
 func (c *GlobalConfig) GetServersSource() string {
@@ -28,8 +38,31 @@ func (c *GlobalConfig) IsRemoteSource() bool {
 	return c.ServersURL != "" && IsURL(c.ServersURL)
 }
 
+// CacheTTL parses URLCacheTTL. It is zero when unset, so that remote servers
+// lists are revalidated on every run.
+func (c *GlobalConfig) CacheTTL() (time.Duration, error) {
+	if c.URLCacheTTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(c.URLCacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid url_cache_ttl: %w", err)
+	}
+	return ttl, nil
+}
+
 var globalUserHomeDir = os.UserHomeDir
 
+// Dir returns sshy's own directory, ~/.sshy, which holds the global config
+// and caches.
+func Dir() (string, error) {
+	home, err := globalUserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".sshy"), nil
+}
+
 func DefaultConfig() *GlobalConfig {
 	home, _ := globalUserHomeDir()
 	return &GlobalConfig{
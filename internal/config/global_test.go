@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -253,3 +254,29 @@ func TestDefaultConfig_UserHomeDirError(t *testing.T) {
 		t.Errorf("Expected ServersPath 'servers.yaml', got '%s'", cfg.ServersPath)
 	}
 }
+
+func TestGlobalConfig_CacheTTL(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"10m", 10 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cfg := &GlobalConfig{URLCacheTTL: tt.value}
+			got, err := cfg.CacheTTL()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CacheTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CacheTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// ResolveJumpChain expands the jump chain of server into the ordered list of
+// hops to traverse. Named hops are looked up in servers so they keep their own
+// user, port, key and options. Like ssh's ProxyJump, only the first hop's own
+// jump chain is followed; later hops are reached through the previous ones.
+func ResolveJumpChain(server models.Server, servers models.Servers) ([]models.Server, error) {
+	byName := make(map[string]models.Server, len(servers))
+	for _, s := range servers {
+		if _, exists := byName[s.Name]; !exists {
+			byName[s.Name] = s
+		}
+	}
+	chain, _, err := resolveJumpChain(server, byName, []string{server.Name})
+	return chain, err
+}
+
+func resolveJumpChain(server models.Server, byName map[string]models.Server, trail []string) ([]models.Server, []string, error) {
+	var chain []models.Server
+	for i, hop := range server.JumpHops() {
+		hopServer, named, err := lookupJumpHop(hop, byName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("server %s: %w", server.Name, err)
+		}
+		if named {
+			for _, seen := range trail {
+				if seen == hopServer.Name {
+					return nil, nil, fmt.Errorf("jump host cycle: %s -> %s", strings.Join(trail, " -> "), hopServer.Name)
+				}
+			}
+			trail = append(trail[:len(trail):len(trail)], hopServer.Name)
+			if i == 0 {
+				var prefix []models.Server
+				prefix, trail, err = resolveJumpChain(hopServer, byName, trail)
+				if err != nil {
+					return nil, nil, err
+				}
+				chain = append(chain, prefix...)
+			}
+		}
+		hopServer.Jump = nil
+		chain = append(chain, hopServer)
+	}
+	return chain, trail, nil
+}
+
+func lookupJumpHop(hop string, byName map[string]models.Server) (models.Server, bool, error) {
+	if s, ok := byName[hop]; ok {
+		return s, true, nil
+	}
+	if !strings.ContainsAny(hop, "@:.") {
+		return models.Server{}, false, fmt.Errorf("unknown jump host: %s", hop)
+	}
+	s, err := ParseJumpHop(hop)
+	return s, false, err
+}
+
+// ParseJumpHop parses a raw [user@]host[:port] hop.
+func ParseJumpHop(hop string) (models.Server, error) {
+	s := models.Server{Name: hop}
+	rest := strings.TrimPrefix(hop, "ssh://")
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		s.User = rest[:at]
+		rest = rest[at+1:]
+	}
+
+	host, port := rest, ""
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return models.Server{}, fmt.Errorf("invalid jump host: %s", hop)
+		}
+		host = rest[1:end]
+		port = strings.TrimPrefix(rest[end+1:], ":")
+	} else if colon := strings.LastIndex(rest, ":"); colon >= 0 && strings.Count(rest, ":") == 1 {
+		host, port = rest[:colon], rest[colon+1:]
+	}
+
+	if host == "" {
+		return models.Server{}, fmt.Errorf("invalid jump host: %s", hop)
+	}
+	s.Host = host
+	if port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil || p <= 0 || p > 65535 {
+			return models.Server{}, fmt.Errorf("invalid port in jump host: %s", hop)
+		}
+		s.Port = p
+	}
+	return s, nil
+}
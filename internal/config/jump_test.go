@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestResolveJumpChain(t *testing.T) {
+	servers := models.Servers{
+		{Name: "bastion", Host: "bastion.example.com", User: "ops", Port: 2222, Key: "~/.ssh/ops"},
+		{Name: "inner", Host: "inner.internal", User: "ops", Jump: models.JumpChain{"bastion"}},
+		{Name: "db", Host: "db.internal", Jump: models.JumpChain{"inner"}},
+		{Name: "web", Host: "web.internal", Jump: models.JumpChain{"admin@edge.example.com:2200", "bastion"}},
+		{Name: "legacy", Host: "legacy.internal", Options: map[string]interface{}{"ProxyJump": "bastion"}},
+		{Name: "loop-a", Host: "a.internal", Jump: models.JumpChain{"loop-b"}},
+		{Name: "loop-b", Host: "b.internal", Jump: models.JumpChain{"loop-a"}},
+		{Name: "self", Host: "self.internal", Jump: models.JumpChain{"self"}},
+		{Name: "typo", Host: "typo.internal", Jump: models.JumpChain{"bastoin"}},
+	}
+
+	find := func(name string) models.Server {
+		for _, s := range servers {
+			if s.Name == name {
+				return s
+			}
+		}
+		t.Fatalf("server %s not in fixture", name)
+		return models.Server{}
+	}
+
+	tests := []struct {
+		name     string
+		server   string
+		expected []string
+		wantErr  string
+	}{
+		{name: "no jump", server: "bastion", expected: nil},
+		{name: "single named hop", server: "inner", expected: []string{"ops@bastion.example.com:2222"}},
+		{name: "first hop chain is expanded", server: "db", expected: []string{"ops@bastion.example.com:2222", "ops@inner.internal:0"}},
+		{name: "raw and named hops", server: "web", expected: []string{"admin@edge.example.com:2200", "ops@bastion.example.com:2222"}},
+		{name: "ProxyJump option", server: "legacy", expected: []string{"ops@bastion.example.com:2222"}},
+		{name: "cycle", server: "loop-a", wantErr: "cycle"},
+		{name: "self reference", server: "self", wantErr: "cycle"},
+		{name: "unknown hop", server: "typo", wantErr: "unknown jump host: bastoin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ResolveJumpChain(find(tt.server), servers)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var got []string
+			for _, hop := range chain {
+				got = append(got, fmt.Sprintf("%s@%s:%d", hop.User, hop.Host, hop.Port))
+				if len(hop.Jump) != 0 {
+					t.Errorf("Expected resolved hop %s to have no jump chain", hop.Name)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected chain %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolveJumpChain_KeepsHopKey(t *testing.T) {
+	servers := models.Servers{
+		{Name: "bastion", Host: "bastion.example.com", Key: "~/.ssh/bastion"},
+		{Name: "app", Host: "app.internal", Key: "~/.ssh/app", Jump: models.JumpChain{"bastion"}},
+	}
+
+	chain, err := ResolveJumpChain(servers[1], servers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(chain) != 1 || chain[0].Key != "~/.ssh/bastion" {
+		t.Errorf("Expected bastion hop with its own key, got %+v", chain)
+	}
+}
+
+func TestParseJumpHop(t *testing.T) {
+	tests := []struct {
+		input   string
+		user    string
+		host    string
+		port    int
+		wantErr bool
+	}{
+		{input: "bastion.example.com", host: "bastion.example.com"},
+		{input: "ops@bastion.example.com", user: "ops", host: "bastion.example.com"},
+		{input: "ops@bastion.example.com:2222", user: "ops", host: "bastion.example.com", port: 2222},
+		{input: "ssh://ops@10.0.0.1:22", user: "ops", host: "10.0.0.1", port: 22},
+		{input: "[fd00::1]:2200", host: "fd00::1", port: 2200},
+		{input: "ops@host:notaport", wantErr: true},
+		{input: "ops@:22", wantErr: true},
+		{input: "[fd00::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			s, err := ParseJumpHop(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJumpHop(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if s.User != tt.user || s.Host != tt.host || s.Port != tt.port {
+				t.Errorf("ParseJumpHop(%q) = %s@%s:%d, expected %s@%s:%d", tt.input, s.User, s.Host, s.Port, tt.user, tt.host, tt.port)
+			}
+		})
+	}
+}
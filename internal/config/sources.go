@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// NamespaceSeparator joins a source's namespace and its server names.
+const NamespaceSeparator = "/"
+
+// MainSource is how the main servers file or URL is named in messages.
+const MainSource = "shared"
+
+// Source is an additional shared servers file or URL.
+type Source struct {
+	Name string `yaml:"name" json:"name"`
+	// Path is relative to the config path unless absolute. Exactly one of
+	// Path and URL is set.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	URL  string `yaml:"url,omitempty" json:"url,omitempty"`
+	// Namespace, if set, prefixes the source's server names as
+	// "namespace/name". Jump hosts naming a server of the same source are
+	// prefixed too.
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// Enabled defaults to true.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// Priority decides which source keeps a server name defined by several:
+	// the highest wins, and ties go to the source listed first. The main
+	// servers file has priority 0.
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty"`
+	// Auth holds the credentials sent to URL.
+	Auth *Auth `yaml:"auth,omitempty" json:"auth,omitempty"`
+}
+
+func (s Source) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// Location returns the source's URL or path.
+func (s Source) Location() string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.Path
+}
+
+// Collision is a server name defined by more than one source, with the
+// source that kept it and the one whose server was dropped.
+type Collision struct {
+	Name    string
+	Kept    string
+	Dropped string
+}
+
+// SharedSources returns the main servers file or URL, with an empty name,
+// followed by the enabled additional sources in the order listed.
+func (c *GlobalConfig) SharedSources() ([]Source, error) {
+	main := Source{Path: c.ServersPath}
+	if c.ServersURL != "" {
+		main = Source{URL: c.ServersURL, Auth: c.ServersAuth}
+	}
+	if err := c.ServersAuth.Validate(); err != nil {
+		return nil, fmt.Errorf("servers_url %w", err)
+	}
+	sources := []Source{main}
+
+	seen := map[string]bool{MainSource: true}
+	for i, s := range c.Sources {
+		switch {
+		case s.Name == "":
+			return nil, fmt.Errorf("source %d has no name", i+1)
+		case seen[s.Name]:
+			return nil, fmt.Errorf("source name %q is used twice", s.Name)
+		case (s.Path == "") == (s.URL == ""):
+			return nil, fmt.Errorf("source %s needs either a path or a url", s.Name)
+		case strings.Contains(s.Namespace, NamespaceSeparator):
+			return nil, fmt.Errorf("namespace of source %s must not contain %q", s.Name, NamespaceSeparator)
+		case s.Auth != nil && s.URL == "":
+			return nil, fmt.Errorf("source %s has auth but no url", s.Name)
+		}
+		if err := s.Auth.Validate(); err != nil {
+			return nil, fmt.Errorf("source %s %w", s.Name, err)
+		}
+		if s.URL != "" {
+			if err := ValidateURL(s.URL); err != nil {
+				return nil, fmt.Errorf("source %s: %w", s.Name, err)
+			}
+		}
+		seen[s.Name] = true
+		if s.IsEnabled() {
+			sources = append(sources, s)
+		}
+	}
+	return sources, nil
+}
+
+// LoadSources loads every shared source in cfg and merges them with the
+// local config. Servers are listed source by source in config order. When
+// sources define the same name, the server of the source with the higher
+// priority is kept in place of the other, and the collision is returned.
+func LoadSources(cfg *GlobalConfig) ([]models.ServerWithSource, []Collision, error) {
+	sources, err := cfg.SharedSources()
+	if err != nil {
+		return nil, nil, err
+	}
+	ttl, err := cfg.CacheTTL()
+	if err != nil {
+		return nil, nil, err
+	}
+	shared, collisions, err := loadShared(cfg.ConfigPath, ttl, sources)
+	if err != nil {
+		return nil, nil, err
+	}
+	localConfig, err := loadLocalConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return mergeSources(shared, localConfig), collisions, nil
+}
+
+// LoadServersWithSourceFromConfig loads the merged inventory of every source
+// in cfg.
+func LoadServersWithSourceFromConfig(cfg *GlobalConfig) ([]models.ServerWithSource, error) {
+	servers, _, err := LoadSources(cfg)
+	return servers, err
+}
+
+// LoadServersFromConfig loads the merged servers of every source in cfg.
+func LoadServersFromConfig(cfg *GlobalConfig) (models.Servers, error) {
+	withSource, err := LoadServersWithSourceFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	servers := make(models.Servers, len(withSource))
+	for i, sws := range withSource {
+		servers[i] = sws.Server
+	}
+	return servers, nil
+}
+
+func loadShared(configPath string, ttl time.Duration, sources []Source) ([]models.ServerWithSource, []Collision, error) {
+	var merged []models.ServerWithSource
+	var priorities []int
+	var collisions []Collision
+	index := make(map[string]int)
+
+	for _, source := range sources {
+		servers, err := loadSource(configPath, ttl, source)
+		if err != nil {
+			if source.Name == "" {
+				return nil, nil, err
+			}
+			return nil, nil, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+		for _, s := range namespaced(servers, source.Namespace) {
+			sws := models.ServerWithSource{Server: s, Source: models.SourceShared, Origin: source.Name}
+			i, taken := index[s.Name]
+			if !taken || merged[i].Origin == source.Name {
+				// Duplicates within a source are kept, as they always were.
+				if !taken {
+					index[s.Name] = len(merged)
+				}
+				merged = append(merged, sws)
+				priorities = append(priorities, source.Priority)
+				continue
+			}
+			collision := Collision{Name: s.Name, Kept: originName(merged[i].Origin), Dropped: originName(source.Name)}
+			if source.Priority > priorities[i] {
+				collision.Kept, collision.Dropped = collision.Dropped, collision.Kept
+				merged[i], priorities[i] = sws, source.Priority
+			}
+			collisions = append(collisions, collision)
+		}
+	}
+	return merged, collisions, nil
+}
+
+func loadSource(configPath string, ttl time.Duration, source Source) (models.Servers, error) {
+	if source.URL != "" {
+		return fetchServers(source.URL, source.Auth, ttl)
+	}
+	if source.Name == "" {
+		return LoadSharedServers(configPath, source.Path)
+	}
+	dir, file := configPath, source.Path
+	if strings.HasPrefix(file, "~/") {
+		home, err := userHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		file = filepath.Join(home, file[2:])
+	}
+	if filepath.IsAbs(file) {
+		dir, file = filepath.Split(file)
+	}
+	// Unlike the main servers file, a listed source must exist.
+	if path, _ := findConfigFile(dir, file); !fileExists(path) {
+		return nil, fmt.Errorf("%s not found", filepath.Join(dir, file))
+	}
+	return LoadSharedServers(dir, file)
+}
+
+// namespaced prefixes server names, and jump hosts naming a server of the
+// same list, with namespace.
+func namespaced(servers models.Servers, namespace string) models.Servers {
+	if namespace == "" {
+		return servers
+	}
+	names := make(map[string]bool, len(servers))
+	for _, s := range servers {
+		names[s.Name] = true
+	}
+	result := make(models.Servers, len(servers))
+	for i, s := range servers {
+		s.Name = namespace + NamespaceSeparator + s.Name
+		if hops := s.JumpHops(); len(hops) > 0 {
+			// A ProxyJump option becomes the jump chain so that its hops
+			// are namespaced too.
+			options := make(map[string]interface{}, len(s.Options))
+			for key, value := range s.Options {
+				if !strings.EqualFold(key, "ProxyJump") {
+					options[key] = value
+				}
+			}
+			if len(options) == 0 {
+				options = nil
+			}
+			s.Options = options
+			jump := make(models.JumpChain, len(hops))
+			for j, hop := range hops {
+				if names[hop] {
+					hop = namespace + NamespaceSeparator + hop
+				}
+				jump[j] = hop
+			}
+			s.Jump = jump
+		}
+		result[i] = s
+	}
+	return result
+}
+
+func originName(origin string) string {
+	if origin == "" {
+		return MainSource
+	}
+	return origin
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSharedSources(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name    string
+		cfg     GlobalConfig
+		names   []string
+		wantErr string
+	}{
+		{
+			name:  "main file only",
+			cfg:   GlobalConfig{ServersPath: "servers.yaml"},
+			names: []string{""},
+		},
+		{
+			name: "disabled sources left out",
+			cfg: GlobalConfig{ServersPath: "servers.yaml", Sources: []Source{
+				{Name: "platform", Path: "platform.yaml"},
+				{Name: "security", Path: "security.yaml", Enabled: &disabled},
+				{Name: "data", URL: "https://example.com/servers.yaml"},
+			}},
+			names: []string{"", "platform", "data"},
+		},
+		{
+			name:    "missing name",
+			cfg:     GlobalConfig{Sources: []Source{{Path: "a.yaml"}}},
+			wantErr: "source 1 has no name",
+		},
+		{
+			name:    "reserved name",
+			cfg:     GlobalConfig{Sources: []Source{{Name: "shared", Path: "a.yaml"}}},
+			wantErr: "used twice",
+		},
+		{
+			name:    "path and url",
+			cfg:     GlobalConfig{Sources: []Source{{Name: "a", Path: "a.yaml", URL: "https://example.com"}}},
+			wantErr: "either a path or a url",
+		},
+		{
+			name:    "invalid url",
+			cfg:     GlobalConfig{Sources: []Source{{Name: "a", URL: "ftp://example.com"}}},
+			wantErr: "http or https",
+		},
+		{
+			name:    "auth without url",
+			cfg:     GlobalConfig{Sources: []Source{{Name: "a", Path: "a.yaml", Auth: &Auth{Bearer: &Secret{Env: "TOKEN"}}}}},
+			wantErr: "has auth but no url",
+		},
+		{
+			name:    "invalid servers_url auth",
+			cfg:     GlobalConfig{ServersURL: "https://example.com", ServersAuth: &Auth{Bearer: &Secret{}}},
+			wantErr: "servers_url auth: bearer",
+		},
+		{
+			name:    "separator in namespace",
+			cfg:     GlobalConfig{Sources: []Source{{Name: "a", Path: "a.yaml", Namespace: "x/y"}}},
+			wantErr: "must not contain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, err := tt.cfg.SharedSources()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var names []string
+			for _, s := range sources {
+				names = append(names, s.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.names, ",") {
+				t.Errorf("Expected sources %v, got %v", tt.names, names)
+			}
+		})
+	}
+}
+
+func writeServers(t *testing.T, path string, servers models.Servers) {
+	t.Helper()
+	data, _ := yaml.Marshal(servers)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoadSources(t *testing.T) {
+	configDir, cleanup := setupTestDir(t)
+	defer cleanup()
+	homeDir, homeCleanup := setupTestHomeDir(t)
+	defer homeCleanup()
+
+	writeServers(t, filepath.Join(configDir, "servers.yaml"), models.Servers{
+		{Name: "web-1", Host: "10.0.0.1"},
+		{Name: "bastion", Host: "bastion.example.com"},
+	})
+	platformPath := filepath.Join(t.TempDir(), "platform.yaml")
+	writeServers(t, platformPath, models.Servers{
+		{Name: "web-1", Host: "10.1.0.1"},
+		{Name: "ci", Host: "10.1.0.2"},
+	})
+	writeServers(t, filepath.Join(configDir, "security.yaml"), models.Servers{
+		{Name: "ci", Host: "10.2.0.2"},
+	})
+	data, _ := yaml.Marshal(models.Servers{
+		{Name: "bastion", Host: "gw.data.example.com"},
+		{Name: "db-1", Host: "10.3.0.1", Jump: models.JumpChain{"bastion", "deploy@edge:2222"}},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	local := LocalConfig{Servers: map[string]models.Server{"data/db-1": {User: "analyst"}}}
+	localData, _ := yaml.Marshal(local)
+	os.WriteFile(filepath.Join(homeDir, ".sshy", "local.yaml"), localData, 0644)
+
+	cfg := &GlobalConfig{ConfigPath: configDir, ServersPath: "servers.yaml", Sources: []Source{
+		{Name: "platform", Path: platformPath, Priority: 10},
+		{Name: "security", Path: "security.yaml", Priority: -1},
+		{Name: "data", URL: server.URL + "/servers.yaml", Namespace: "data"},
+	}}
+	servers, collisions, err := LoadSources(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		name, host, origin string
+		source             models.ServerSource
+	}{
+		{"web-1", "10.1.0.1", "platform", models.SourceShared},
+		{"bastion", "bastion.example.com", "", models.SourceShared},
+		{"ci", "10.1.0.2", "platform", models.SourceShared},
+		{"data/bastion", "gw.data.example.com", "data", models.SourceShared},
+		{"data/db-1", "10.3.0.1", "data", models.SourceOverride},
+	}
+	if len(servers) != len(expected) {
+		t.Fatalf("Expected %d servers, got %+v", len(expected), servers)
+	}
+	for i, want := range expected {
+		got := servers[i]
+		if got.Server.Name != want.name || got.Server.Host != want.host || got.Origin != want.origin || got.Source != want.source {
+			t.Errorf("Expected %s at %s from %q (%d), got %s at %s from %q (%d)",
+				want.name, want.host, want.origin, want.source, got.Server.Name, got.Server.Host, got.Origin, got.Source)
+		}
+	}
+	db := servers[4].Server
+	if db.User != "analyst" || strings.Join(db.Jump, ",") != "data/bastion,deploy@edge:2222" {
+		t.Errorf("Expected the override and a namespaced jump host, got %+v", db)
+	}
+
+	expectedCollisions := []Collision{
+		{Name: "web-1", Kept: "platform", Dropped: "shared"},
+		{Name: "ci", Kept: "platform", Dropped: "security"},
+	}
+	if len(collisions) != len(expectedCollisions) {
+		t.Fatalf("Expected %d collisions, got %+v", len(expectedCollisions), collisions)
+	}
+	for i, want := range expectedCollisions {
+		if collisions[i] != want {
+			t.Errorf("Expected collision %+v, got %+v", want, collisions[i])
+		}
+	}
+}
+
+func TestNamespaced_ProxyJumpOption(t *testing.T) {
+	servers := namespaced(models.Servers{
+		{Name: "bastion", Host: "gw.example.com"},
+		{Name: "db", Host: "10.0.0.5", Options: map[string]interface{}{"ProxyJump": "bastion,edge.example.com", "ForwardAgent": "yes"}},
+		{Name: "direct", Host: "10.0.0.6", Options: map[string]interface{}{"proxyjump": "none"}},
+	}, "platform")
+
+	db := servers[1]
+	if strings.Join(db.JumpHops(), ",") != "platform/bastion,edge.example.com" {
+		t.Errorf("Expected the ProxyJump hops to be namespaced, got %v", db.JumpHops())
+	}
+	if _, ok := db.Options["ProxyJump"]; ok || db.Options["ForwardAgent"] != "yes" {
+		t.Errorf("Expected ProxyJump to move to the jump chain and other options to stay, got %v", db.Options)
+	}
+	if direct := servers[2]; len(direct.JumpHops()) != 0 || direct.Options["proxyjump"] != "none" {
+		t.Errorf("Expected ProxyJump none to be kept, got %+v", direct)
+	}
+}
+
+func TestLoadSources_MissingFile(t *testing.T) {
+	configDir, cleanup := setupTestDir(t)
+	defer cleanup()
+	_, homeCleanup := setupTestHomeDir(t)
+	defer homeCleanup()
+
+	cfg := &GlobalConfig{ConfigPath: configDir, ServersPath: "servers.yaml", Sources: []Source{
+		{Name: "platform", Path: "platform.yaml"},
+	}}
+	_, _, err := LoadSources(cfg)
+	if err == nil || !strings.Contains(err.Error(), "source platform") {
+		t.Errorf("Expected an error naming the source, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -16,7 +17,8 @@ const (
 )
 
 var httpClient = &http.Client{
-	Timeout: DefaultHTTPTimeout,
+	Timeout:       DefaultHTTPTimeout,
+	CheckRedirect: dropRedirectAuth,
 }
 
 func IsURL(path string) bool {
@@ -44,17 +46,110 @@ func ValidateURL(urlStr string) error {
 	return nil
 }
 
+// ForceRefresh makes remote servers lists be fetched even when the cached copy
+// is younger than the cache TTL. It is set by --refresh-servers.
+var ForceRefresh bool
+
+// Offline makes remote servers lists be read from the cache only, without
+// asking the server or printing warnings. A list that was never cached is
+// empty. It is set by "sshy proxy", which ssh runs for every connection.
+var Offline bool
+
+// warnOutput receives the warning printed when a cached copy is used because
+// the URL could not be fetched.
+var warnOutput io.Writer = os.Stderr
+
+// FetchServersFromURL downloads a servers list, always asking the server. See
+// fetchServers for the cache.
 func FetchServersFromURL(urlStr string) (models.Servers, error) {
+	return fetchServers(urlStr, nil, 0)
+}
+
+// FetchURL downloads the document at urlStr as it is, with the credentials of
+// auth, which may be nil. The cache is neither used nor updated.
+func FetchURL(urlStr string, auth *Auth) ([]byte, error) {
 	if err := ValidateURL(urlStr); err != nil {
 		return nil, err
 	}
+	resp, err := download(urlStr, auth, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(resp.Body), nil
+}
+
+// fetchServers returns the servers list at urlStr, sending the credentials of
+// auth, which may be nil. The last good response is
+// cached and revalidated with If-None-Match and If-Modified-Since. While the
+// cached copy is younger than ttl, the server is not asked at all unless
+// ForceRefresh is set, and never when Offline is set. If the fetch fails, the cached copy is used with a
+// warning.
+func fetchServers(urlStr string, auth *Auth, ttl time.Duration) (models.Servers, error) {
+	if err := ValidateURL(urlStr); err != nil {
+		return nil, err
+	}
+
+	cached := loadCachedResponse(urlStr)
+	if Offline {
+		if cached == nil {
+			return models.Servers{}, nil
+		}
+		return parseResponse(cached)
+	}
+	if cached != nil && ttl > 0 && !ForceRefresh && time.Since(cached.FetchedAt) < ttl {
+		return parseResponse(cached)
+	}
+
+	fresh, err := download(urlStr, auth, cached)
+	var servers models.Servers
+	if err == nil {
+		servers, err = parseResponse(fresh)
+	}
+	if err != nil {
+		if cached == nil {
+			return nil, err
+		}
+		fmt.Fprintf(warnOutput, "Warning: %v; using the cached copy of %s, stale since %s\n",
+			err, urlStr, cached.FetchedAt.Local().Format(time.RFC1123))
+		return parseResponse(cached)
+	}
+
+	// A failure to cache must not break the command.
+	_ = saveCachedResponse(fresh)
+	return servers, nil
+}
+
+// download fetches urlStr, sending the validators of cached if there is one.
+// On 304 Not Modified the cached body is returned with a new fetch time.
+func download(urlStr string, auth *Auth, cached *cachedResponse) (*cachedResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req, err = auth.apply(req)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 
-	resp, err := httpClient.Get(urlStr)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from URL: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		revalidated := *cached
+		revalidated.FetchedAt = time.Now()
+		return &revalidated, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
 	}
@@ -64,16 +159,28 @@ func FetchServersFromURL(urlStr string) (models.Servers, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	return &cachedResponse{
+		URL:          urlStr,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		FetchedAt:    time.Now(),
+		Body:         string(data),
+	}, nil
+}
+
+func parseResponse(resp *cachedResponse) (models.Servers, error) {
+	data := []byte(resp.Body)
 	if len(data) == 0 {
 		return models.Servers{}, nil
 	}
 
 	format := DetectFormatFromContent(data)
 	if format == FormatUnknown {
-		format = detectFormatFromContentType(resp.Header.Get("Content-Type"))
+		format = detectFormatFromContentType(resp.ContentType)
 	}
 	if format == FormatUnknown {
-		format = detectFormatFromURL(urlStr)
+		format = detectFormatFromURL(resp.URL)
 	}
 	if format == FormatUnknown {
 		format = FormatYAML
@@ -0,0 +1,218 @@
+// Package discover finds servers the user connects to that are not in the
+// inventory yet, from known_hosts and shell history.
+package discover
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// Endpoint is a host as given to ssh. Port is 0 for the default port.
+type Endpoint struct {
+	Host string
+	User string
+	Port int
+}
+
+// Candidate is a host that may be worth adding, with the user most often
+// used to log in to it.
+type Candidate struct {
+	Endpoint
+	// Uses counts the ssh invocations in shell history.
+	Uses int
+	// KnownHost is set when the host is in known_hosts.
+	KnownHost bool
+}
+
+// Server returns the candidate as a server named after its host.
+func (c Candidate) Server() models.Server {
+	return models.Server{Name: c.Host, Host: c.Host, User: c.User, Port: c.Port}
+}
+
+// Sources are the files read by Scan.
+type Sources struct {
+	KnownHosts []string
+	History    map[Shell][]string
+}
+
+// DefaultSources returns the usual locations under home. Files that do not
+// exist are skipped by Scan.
+func DefaultSources(home string) Sources {
+	fishDir := filepath.Join(home, ".local", "share")
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		fishDir = dir
+	}
+	return Sources{
+		KnownHosts: []string{filepath.Join(home, ".ssh", "known_hosts")},
+		History: map[Shell][]string{
+			Bash: {filepath.Join(home, ".bash_history")},
+			Zsh:  {filepath.Join(home, ".zsh_history"), filepath.Join(home, ".zhistory")},
+			Fish: {filepath.Join(fishDir, "fish", "fish_history")},
+		},
+	}
+}
+
+// Result is the outcome of a scan.
+type Result struct {
+	Candidates []Candidate
+	// Unresolved counts the hashed known_hosts lines that matched none of
+	// the hosts seen in history.
+	Unresolved int
+}
+
+// Scan reads the sources and ranks every host found.
+func Scan(sources Sources) (Result, error) {
+	var history []Endpoint
+	for _, shell := range []Shell{Bash, Zsh, Fish} {
+		for _, path := range sources.History[shell] {
+			f, err := os.Open(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return Result{}, err
+			}
+			endpoints, err := ParseHistory(f, shell)
+			f.Close()
+			if err != nil {
+				return Result{}, err
+			}
+			history = append(history, endpoints...)
+		}
+	}
+
+	var known []KnownHost
+	for _, path := range sources.KnownHosts {
+		f, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Result{}, err
+		}
+		hosts, err := ParseKnownHosts(f)
+		f.Close()
+		if err != nil {
+			return Result{}, err
+		}
+		known = append(known, hosts...)
+	}
+
+	return Rank(known, history), nil
+}
+
+type endpointKey struct {
+	host string
+	port int
+}
+
+// Rank merges known hosts and history into candidates, most used first.
+// Hashed known_hosts lines are matched against the hosts seen in history;
+// the others are counted as unresolved. Local addresses are left out.
+func Rank(known []KnownHost, history []Endpoint) Result {
+	var result Result
+	var order []endpointKey
+	candidates := make(map[endpointKey]*Candidate)
+	users := make(map[endpointKey]map[string]int)
+
+	get := func(key endpointKey) *Candidate {
+		c, ok := candidates[key]
+		if !ok {
+			c = &Candidate{Endpoint: Endpoint{Host: key.host, Port: key.port}}
+			candidates[key] = c
+			users[key] = make(map[string]int)
+			order = append(order, key)
+		}
+		return c
+	}
+
+	for _, e := range history {
+		if isLocal(e.Host) {
+			continue
+		}
+		key := endpointKey{e.Host, e.Port}
+		c := get(key)
+		c.Uses++
+		if e.User != "" {
+			users[key][e.User]++
+			// Ties go to the most recent user.
+			if users[key][e.User] >= users[key][c.User] {
+				c.User = e.User
+			}
+		}
+	}
+
+	for _, k := range known {
+		if k.Hashed() {
+			matched := false
+			for _, key := range order {
+				if k.Matches(key.host, key.port) {
+					candidates[key].KnownHost = true
+					matched = true
+				}
+			}
+			if !matched {
+				result.Unresolved++
+			}
+			continue
+		}
+		// A line lists a host under its names and addresses; the first one
+		// already in history stands for the host, else the first name.
+		key := endpointKey{k.Names[0], k.Port}
+		for _, name := range k.Names {
+			if _, ok := candidates[endpointKey{name, k.Port}]; ok {
+				key = endpointKey{name, k.Port}
+				break
+			}
+		}
+		if isLocal(key.host) {
+			continue
+		}
+		get(key).KnownHost = true
+	}
+
+	for _, key := range order {
+		result.Candidates = append(result.Candidates, *candidates[key])
+	}
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		a, b := result.Candidates[i], result.Candidates[j]
+		if a.Uses != b.Uses {
+			return a.Uses > b.Uses
+		}
+		return a.KnownHost && !b.KnownHost
+	})
+	return result
+}
+
+// NotIn returns the candidates that match no server, by name or by host and
+// port.
+func NotIn(candidates []Candidate, servers models.Servers) []Candidate {
+	var unknown []Candidate
+	for _, c := range candidates {
+		found := false
+		for _, s := range servers {
+			port := s.Port
+			if port == 22 {
+				port = 0
+			}
+			if strings.EqualFold(s.Name, c.Host) || (strings.EqualFold(s.Host, c.Host) && port == c.Port) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, c)
+		}
+	}
+	return unknown
+}
+
+func isLocal(host string) bool {
+	return host == "localhost" || host == "::1" || strings.HasPrefix(host, "127.")
+}
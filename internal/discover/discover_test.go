@@ -0,0 +1,191 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+const (
+	hashedHost   = "|1|MDEyMzQ1Njc4OWFiY2RlZmdoaWo=|gzfxEI74iflku6CWHlY6D9H4tKY="
+	hashedSecret = "|1|MDEyMzQ1Njc4OWFiY2RlZmdoaWo=|591VqbcHERQfshV270geS7raYAI="
+)
+
+func TestParseSSH(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []Endpoint
+	}{
+		{"ssh web.example.com", []Endpoint{{Host: "web.example.com"}}},
+		{"ssh deploy@Web.Example.com uptime", []Endpoint{{Host: "web.example.com", User: "deploy"}}},
+		{"ssh -p 2222 -l admin db", []Endpoint{{Host: "db", User: "admin", Port: 2222}}},
+		{"ssh -vp2222 -i ~/.ssh/key db -- ls -p 22", []Endpoint{{Host: "db", Port: 2222}}},
+		{"ssh db -o Port=2200 -o 'User ops'", []Endpoint{{Host: "db", User: "ops", Port: 2200}}},
+		{"ssh -l admin root@db", []Endpoint{{Host: "db", User: "admin"}}},
+		{"ssh -p 22 db", []Endpoint{{Host: "db"}}},
+		{"ssh ssh://git@example.org:7999", []Endpoint{{Host: "example.org", User: "git", Port: 7999}}},
+		{"sudo TERM=xterm /usr/bin/ssh host1 && ssh host2 | tee log", []Endpoint{{Host: "host1"}, {Host: "host2"}}},
+		{`ssh "$HOST"`, nil},
+		{"ssh web-*", nil},
+		{"ssh-keygen -R host", nil},
+		{"echo ssh host", nil},
+		{"git push", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			endpoints, err := ParseHistory(strings.NewReader(tt.line), Bash)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(endpoints, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, endpoints)
+			}
+		})
+	}
+}
+
+func TestParseHistory_Formats(t *testing.T) {
+	tests := []struct {
+		shell   Shell
+		history string
+	}{
+		{Bash, "#1700000000\nssh a.example.com\nls\n#1700000001\nssh b.example.com\n"},
+		{Zsh, ": 1700000000:0;ssh a.example.com\n: 1700000001:0;cd /tmp\nssh b.example.com\n"},
+		{Fish, "- cmd: ssh a.example.com\n  when: 1700000000\n- cmd: echo one\\nssh b.example.com\n  when: 1700000001\n"},
+	}
+
+	expected := []Endpoint{{Host: "a.example.com"}, {Host: "b.example.com"}}
+	for _, tt := range tests {
+		t.Run(string(tt.shell), func(t *testing.T) {
+			endpoints, err := ParseHistory(strings.NewReader(tt.history), tt.shell)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(endpoints, expected) {
+				t.Errorf("Expected %+v, got %+v", expected, endpoints)
+			}
+		})
+	}
+}
+
+func TestParseKnownHosts(t *testing.T) {
+	data := strings.Join([]string{
+		"# comment",
+		"github.com,140.82.121.3 ssh-ed25519 AAAA",
+		"[git.example.com]:2222 ssh-ed25519 AAAA",
+		"[plain.example.com]:22 ssh-ed25519 AAAA",
+		"*.example.com ssh-ed25519 AAAA",
+		"@cert-authority *.example.com ssh-ed25519 AAAA",
+		hashedHost + " ssh-ed25519 AAAA",
+		"|1|bad|hash ssh-ed25519 AAAA",
+	}, "\n")
+
+	hosts, err := ParseKnownHosts(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(hosts) != 4 {
+		t.Fatalf("Expected 4 entries, got %d: %+v", len(hosts), hosts)
+	}
+	if !reflect.DeepEqual(hosts[0].Names, []string{"github.com", "140.82.121.3"}) || hosts[0].Port != 0 {
+		t.Errorf("Expected github.com and its address, got %+v", hosts[0])
+	}
+	if hosts[1].Names[0] != "git.example.com" || hosts[1].Port != 2222 {
+		t.Errorf("Expected git.example.com on port 2222, got %+v", hosts[1])
+	}
+	if hosts[2].Names[0] != "plain.example.com" || hosts[2].Port != 0 {
+		t.Errorf("Expected plain.example.com on the default port, got %+v", hosts[2])
+	}
+	if !hosts[3].Hashed() || !hosts[3].Matches("hashed.example.com", 0) || hosts[3].Matches("other.example.com", 0) {
+		t.Errorf("Expected a hashed entry for hashed.example.com only, got %+v", hosts[3])
+	}
+}
+
+func TestRank(t *testing.T) {
+	known, _ := ParseKnownHosts(strings.NewReader(strings.Join([]string{
+		"web.example.com,10.0.0.1 ssh-ed25519 AAAA",
+		"only-known.example.com ssh-ed25519 AAAA",
+		"localhost ssh-ed25519 AAAA",
+		hashedSecret + " ssh-ed25519 AAAA",
+		hashedHost + " ssh-ed25519 AAAA",
+	}, "\n")))
+	history := []Endpoint{
+		{Host: "10.0.0.1", User: "root"},
+		{Host: "secret.example.com", User: "ops", Port: 2200},
+		{Host: "10.0.0.1", User: "deploy"},
+		{Host: "10.0.0.1", User: "root"},
+		{Host: "localhost"},
+		{Host: "once.example.com"},
+		{Host: "secret.example.com", User: "admin", Port: 2200},
+	}
+
+	result := Rank(known, history)
+	expected := []Candidate{
+		{Endpoint: Endpoint{Host: "10.0.0.1", User: "root"}, Uses: 3, KnownHost: true},
+		{Endpoint: Endpoint{Host: "secret.example.com", User: "admin", Port: 2200}, Uses: 2, KnownHost: true},
+		{Endpoint: Endpoint{Host: "once.example.com"}, Uses: 1},
+		{Endpoint: Endpoint{Host: "only-known.example.com"}, KnownHost: true},
+	}
+	if !reflect.DeepEqual(result.Candidates, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result.Candidates)
+	}
+	if result.Unresolved != 1 {
+		t.Errorf("Expected 1 unresolved hashed entry, got %d", result.Unresolved)
+	}
+}
+
+func TestNotIn(t *testing.T) {
+	candidates := []Candidate{
+		{Endpoint: Endpoint{Host: "web-1"}},
+		{Endpoint: Endpoint{Host: "10.0.0.1"}},
+		{Endpoint: Endpoint{Host: "10.0.0.1", Port: 2222}},
+		{Endpoint: Endpoint{Host: "10.0.0.2", Port: 2222}},
+	}
+	servers := models.Servers{
+		{Name: "web-1", Host: "web-1.example.com"},
+		{Name: "db", Host: "10.0.0.1", Port: 22},
+		{Name: "app", Host: "10.0.0.2", Port: 2222},
+	}
+
+	unknown := NotIn(candidates, servers)
+	if len(unknown) != 1 || unknown[0].Host != "10.0.0.1" || unknown[0].Port != 2222 {
+		t.Errorf("Expected only 10.0.0.1:2222, got %+v", unknown)
+	}
+}
+
+func TestScan(t *testing.T) {
+	home := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(home, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".ssh/known_hosts", "a.example.com ssh-ed25519 AAAA\n")
+	write(".bash_history", "ssh b.example.com\n")
+	write(".zsh_history", ": 1700000000:0;ssh b.example.com\n")
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	write("data/fish/fish_history", "- cmd: ssh -p 2200 c.example.com\n")
+
+	result, err := Scan(DefaultSources(home))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var got []string
+	for _, c := range result.Candidates {
+		got = append(got, c.Host)
+	}
+	expected := []string{"b.example.com", "c.example.com", "a.example.com"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
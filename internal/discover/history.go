@@ -0,0 +1,284 @@
+package discover
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Shell names a history file format.
+type Shell string
+
+const (
+	Bash Shell = "bash"
+	Zsh  Shell = "zsh"
+	Fish Shell = "fish"
+)
+
+// sshArgOptions are the ssh flags that take an argument.
+const sshArgOptions = "BbcDEeFIiJLlmOoPpQRSWw"
+
+// wrappers are commands that run the rest of their command line, so that
+// "sudo ssh host" still counts as an ssh invocation.
+var wrappers = map[string]bool{
+	"sudo":    true,
+	"command": true,
+	"exec":    true,
+	"time":    true,
+	"nohup":   true,
+}
+
+// ParseHistory returns the destination of every ssh invocation in a shell
+// history file, oldest first.
+func ParseHistory(r io.Reader, shell Shell) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line, ok := historyCommand(scanner.Text(), shell)
+		if !ok {
+			continue
+		}
+		for _, words := range splitCommands(line) {
+			if e, ok := parseSSH(words); ok {
+				endpoints = append(endpoints, e)
+			}
+		}
+	}
+	return endpoints, scanner.Err()
+}
+
+// historyCommand extracts the command from a history line, reporting false
+// for lines holding only metadata.
+func historyCommand(line string, shell Shell) (string, bool) {
+	switch shell {
+	case Bash:
+		// Timestamps written with HISTTIMEFORMAT set.
+		if strings.HasPrefix(line, "#") {
+			return "", false
+		}
+	case Zsh:
+		// Extended history: ": <start>:<elapsed>;<command>".
+		if strings.HasPrefix(line, ": ") {
+			i := strings.IndexByte(line, ';')
+			if i < 0 {
+				return "", false
+			}
+			line = line[i+1:]
+		}
+	case Fish:
+		cmd, ok := strings.CutPrefix(line, "- cmd: ")
+		if !ok {
+			return "", false
+		}
+		line = strings.NewReplacer(`\\`, `\`, `\n`, "\n").Replace(cmd)
+	}
+	return line, true
+}
+
+// splitCommands splits a command line into the words of each simple command,
+// honouring quotes and backslashes. Commands are separated by newlines, ";",
+// "|" and "&".
+func splitCommands(line string) [][]string {
+	var commands [][]string
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			commands = append(commands, words)
+			words = nil
+		}
+	}
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				escaped = true
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			endWord()
+		case r == '\n' || r == ';' || r == '|' || r == '&':
+			endCommand()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	endCommand()
+	return commands
+}
+
+// parseSSH reads the destination, user and port of an ssh command, after any
+// variable assignments and wrapper commands.
+func parseSSH(words []string) (Endpoint, bool) {
+	for len(words) > 0 && (wrappers[words[0]] || isAssignment(words[0])) {
+		words = words[1:]
+	}
+	if len(words) == 0 || filepath.Base(words[0]) != "ssh" {
+		return Endpoint{}, false
+	}
+
+	var e Endpoint
+	var optUser string
+	destination := ""
+	args := words[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			if destination == "" && i+1 < len(args) {
+				destination = args[i+1]
+			}
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			// ssh reads options after the destination too, up to the
+			// remote command.
+			if destination != "" {
+				break
+			}
+			destination = arg
+			continue
+		}
+		for j := 1; j < len(arg); j++ {
+			flag := arg[j]
+			if !strings.ContainsRune(sshArgOptions, rune(flag)) {
+				continue
+			}
+			value := arg[j+1:]
+			if value == "" && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			switch flag {
+			case 'l':
+				if optUser == "" {
+					optUser = value
+				}
+			case 'p':
+				if e.Port == 0 {
+					e.Port = parsePort(value)
+				}
+			case 'o':
+				key, val := splitOption(value)
+				switch strings.ToLower(key) {
+				case "user":
+					if optUser == "" {
+						optUser = val
+					}
+				case "port":
+					if e.Port == 0 {
+						e.Port = parsePort(val)
+					}
+				}
+			}
+			break
+		}
+	}
+
+	user, host, port, ok := parseDestination(destination)
+	if !ok {
+		return Endpoint{}, false
+	}
+	e.Host = host
+	// The first user given wins, as in ssh; -l and -o User usually come
+	// before the destination.
+	e.User = optUser
+	if e.User == "" {
+		e.User = user
+	}
+	if e.Port == 0 {
+		e.Port = port
+	}
+	if e.Port == 22 {
+		e.Port = 0
+	}
+	return e, true
+}
+
+// parseDestination splits [user@]host or ssh://[user@]host[:port].
+func parseDestination(dest string) (user, host string, port int, ok bool) {
+	if strings.HasPrefix(dest, "ssh://") {
+		u, err := url.Parse(dest)
+		if err != nil {
+			return "", "", 0, false
+		}
+		host = u.Hostname()
+		port = parsePort(u.Port())
+		if u.User != nil {
+			user = u.User.Username()
+		}
+	} else if i := strings.LastIndexByte(dest, '@'); i >= 0 {
+		user, host = dest[:i], dest[i+1:]
+	} else {
+		host = dest
+	}
+	if !validHost(host) {
+		return "", "", 0, false
+	}
+	return user, strings.ToLower(host), port, true
+}
+
+func splitOption(option string) (string, string) {
+	key, value, found := strings.Cut(option, "=")
+	if !found {
+		key, value, _ = strings.Cut(option, " ")
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(value)
+}
+
+func parsePort(s string) int {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0
+	}
+	return port
+}
+
+func isAssignment(word string) bool {
+	name, _, found := strings.Cut(word, "=")
+	return found && name != "" && !strings.ContainsAny(name, "-/.")
+}
+
+// validHost accepts host names and IP addresses, leaving out shell variables,
+// globs and other words that cannot be resolved as written.
+func validHost(host string) bool {
+	if host == "" || strings.HasPrefix(host, "-") || strings.HasPrefix(host, ".") {
+		return false
+	}
+	for _, r := range host {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.' || r == '-' || r == '_' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package discover
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// KnownHost is a known_hosts line. Names holds the plain host names and
+// addresses; a hashed line has only Salt and Hash.
+type KnownHost struct {
+	Names []string
+	Port  int
+	Salt  []byte
+	Hash  []byte
+}
+
+// Hashed reports whether the line was written with HashKnownHosts.
+func (k KnownHost) Hashed() bool {
+	return k.Hash != nil
+}
+
+// Matches reports whether a hashed line is for host and port.
+func (k KnownHost) Matches(host string, port int) bool {
+	mac := hmac.New(sha1.New, k.Salt)
+	mac.Write([]byte(knownHostsName(host, port)))
+	return hmac.Equal(mac.Sum(nil), k.Hash)
+}
+
+// ParseKnownHosts reads a known_hosts file. Wildcard and negated patterns,
+// @cert-authority and @revoked lines and malformed hashes are left out.
+func ParseKnownHosts(r io.Reader) ([]KnownHost, error) {
+	var hosts []KnownHost
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "@") {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "|1|") {
+			if k, ok := parseHashed(fields[0]); ok {
+				hosts = append(hosts, k)
+			}
+			continue
+		}
+
+		var k KnownHost
+		for _, pattern := range strings.Split(fields[0], ",") {
+			if strings.ContainsAny(pattern, "*?!") {
+				continue
+			}
+			host, port := splitKnownHostsName(pattern)
+			if !validHost(host) {
+				continue
+			}
+			k.Names = append(k.Names, strings.ToLower(host))
+			k.Port = port
+		}
+		if len(k.Names) > 0 {
+			hosts = append(hosts, k)
+		}
+	}
+	return hosts, scanner.Err()
+}
+
+func parseHashed(field string) (KnownHost, bool) {
+	parts := strings.Split(field, "|")
+	if len(parts) != 4 {
+		return KnownHost{}, false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return KnownHost{}, false
+	}
+	hash, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil || len(hash) != sha1.Size {
+		return KnownHost{}, false
+	}
+	return KnownHost{Salt: salt, Hash: hash}, true
+}
+
+// knownHostsName is how ssh writes a host in known_hosts: bare for port 22,
+// "[host]:port" otherwise.
+func knownHostsName(host string, port int) string {
+	if port == 0 || port == 22 {
+		return host
+	}
+	return "[" + host + "]:" + strconv.Itoa(port)
+}
+
+func splitKnownHostsName(name string) (string, int) {
+	if strings.HasPrefix(name, "[") {
+		if host, port, err := net.SplitHostPort(name); err == nil {
+			if p := parsePort(port); p != 22 {
+				return host, p
+			}
+			return host, 0
+		}
+	}
+	return name, 0
+}
@@ -0,0 +1,49 @@
+package facts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Cache stores one JSON file of facts per server.
+type Cache struct {
+	Dir string
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{Dir: dir}
+}
+
+func (c *Cache) path(name string) string {
+	return filepath.Join(c.Dir, url.PathEscape(name)+".json")
+}
+
+// Load returns the cached facts for a server. found is false if nothing has
+// been cached yet.
+func (c *Cache) Load(name string) (f Facts, found bool, err error) {
+	data, err := os.ReadFile(c.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return Facts{}, false, nil
+	}
+	if err != nil {
+		return Facts{}, false, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Facts{}, false, err
+	}
+	return f, true, nil
+}
+
+func (c *Cache) Save(name string, f Facts) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path(name), append(data, '\n'), 0644)
+}
@@ -0,0 +1,44 @@
+package facts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(filepath.Join(t.TempDir(), "facts"))
+
+	if _, found, err := cache.Load("web-1"); found || err != nil {
+		t.Fatalf("Expected empty cache, got found=%v err=%v", found, err)
+	}
+
+	collected := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	saved := Facts{CollectedAt: collected, Kernel: "Linux 6.1.0", CPUs: 2, Disks: []Disk{{Mount: "/", SizeBytes: 100, UsedBytes: 40}}}
+	if err := cache.Save("team/web-1", saved); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	loaded, found, err := cache.Load("team/web-1")
+	if err != nil || !found {
+		t.Fatalf("Expected cached facts, got found=%v err=%v", found, err)
+	}
+	if !loaded.CollectedAt.Equal(collected) || loaded.Kernel != saved.Kernel || loaded.CPUs != 2 || len(loaded.Disks) != 1 {
+		t.Errorf("Expected %+v, got %+v", saved, loaded)
+	}
+
+	if _, err := os.Stat(filepath.Join(cache.Dir, "team%2Fweb-1.json")); err != nil {
+		t.Errorf("Expected server name escaped in the file name: %v", err)
+	}
+}
+
+func TestCache_Corrupt(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	if err := os.WriteFile(filepath.Join(cache.Dir, "web-1.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cache.Load("web-1"); err == nil {
+		t.Error("Expected error for corrupt cache file")
+	}
+}
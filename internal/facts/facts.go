@@ -0,0 +1,196 @@
+package facts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+)
+
+// Script collects the facts on the remote server. It only relies on POSIX sh
+// and tools present on common Linux distributions; sections that fail are
+// left empty.
+const Script = `echo '==os=='; cat /etc/os-release 2>/dev/null || sw_vers 2>/dev/null
+echo '==kernel=='; uname -sr
+echo '==uptime=='; cat /proc/uptime 2>/dev/null
+echo '==cpus=='; getconf _NPROCESSORS_ONLN 2>/dev/null || nproc 2>/dev/null
+echo '==memory=='; grep -E '^(MemTotal|MemAvailable):' /proc/meminfo 2>/dev/null
+echo '==disks=='; df -P -k 2>/dev/null
+echo '==addresses=='; hostname -I 2>/dev/null || ip -o addr show scope global 2>/dev/null | awk '{print $4}'
+echo '==end=='`
+
+// Command wraps Script so it runs under sh whatever the login shell is.
+func Command() string {
+	return "sh -c " + sshcmd.Quote(Script)
+}
+
+type Disk struct {
+	Mount     string `json:"mount"`
+	SizeBytes uint64 `json:"size_bytes"`
+	UsedBytes uint64 `json:"used_bytes"`
+}
+
+func (d Disk) UsedPercent() int {
+	if d.SizeBytes == 0 {
+		return 0
+	}
+	return int(d.UsedBytes * 100 / d.SizeBytes)
+}
+
+type Facts struct {
+	CollectedAt          time.Time     `json:"collected_at"`
+	OS                   string        `json:"os,omitempty"`
+	Kernel               string        `json:"kernel,omitempty"`
+	Uptime               time.Duration `json:"uptime,omitempty"`
+	CPUs                 int           `json:"cpus,omitempty"`
+	MemoryTotalBytes     uint64        `json:"memory_total_bytes,omitempty"`
+	MemoryAvailableBytes uint64        `json:"memory_available_bytes,omitempty"`
+	Disks                []Disk        `json:"disks,omitempty"`
+	Addresses            []string      `json:"addresses,omitempty"`
+}
+
+// Stale reports whether the facts are older than maxAge. A zero maxAge never
+// expires.
+func (f Facts) Stale(maxAge time.Duration, now time.Time) bool {
+	return maxAge > 0 && now.Sub(f.CollectedAt) > maxAge
+}
+
+// Summary is a one-line description such as
+// "Ubuntu 22.04.4 LTS, Linux 5.15.0-105-generic, 4 CPUs, 7.8 GiB".
+func (f Facts) Summary() string {
+	var parts []string
+	if f.OS != "" {
+		parts = append(parts, f.OS)
+	}
+	if f.Kernel != "" {
+		parts = append(parts, f.Kernel)
+	}
+	if f.CPUs > 0 {
+		parts = append(parts, fmt.Sprintf("%d CPUs", f.CPUs))
+	}
+	if f.MemoryTotalBytes > 0 {
+		parts = append(parts, FormatBytes(f.MemoryTotalBytes))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Parse reads the output of Script.
+func Parse(output string) (Facts, error) {
+	sections := make(map[string][]string)
+	current := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "==") && strings.HasSuffix(line, "==") && len(line) > 4 {
+			current = strings.Trim(line, "=")
+			sections[current] = []string{}
+			continue
+		}
+		if current != "" && strings.TrimSpace(line) != "" {
+			sections[current] = append(sections[current], line)
+		}
+	}
+	if _, ok := sections["end"]; !ok {
+		return Facts{}, fmt.Errorf("unexpected probe output")
+	}
+
+	var f Facts
+	f.OS = parseOS(sections["os"])
+	if lines := sections["kernel"]; len(lines) > 0 {
+		f.Kernel = strings.TrimSpace(lines[0])
+	}
+	if lines := sections["uptime"]; len(lines) > 0 {
+		if fields := strings.Fields(lines[0]); len(fields) > 0 {
+			if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil {
+				f.Uptime = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	if lines := sections["cpus"]; len(lines) > 0 {
+		f.CPUs, _ = strconv.Atoi(strings.TrimSpace(lines[0]))
+	}
+	for _, line := range sections["memory"] {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			f.MemoryTotalBytes = kb * 1024
+		case "MemAvailable:":
+			f.MemoryAvailableBytes = kb * 1024
+		}
+	}
+	f.Disks = parseDisks(sections["disks"])
+	for _, line := range sections["addresses"] {
+		for _, addr := range strings.Fields(line) {
+			f.Addresses = append(f.Addresses, strings.SplitN(addr, "/", 2)[0])
+		}
+	}
+	return f, nil
+}
+
+// parseOS reads /etc/os-release, falling back to the sw_vers output on macOS.
+func parseOS(lines []string) string {
+	values := make(map[string]string)
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if ok {
+			values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	switch {
+	case values["PRETTY_NAME"] != "":
+		return values["PRETTY_NAME"]
+	case values["NAME"] != "":
+		return strings.TrimSpace(values["NAME"] + " " + values["VERSION"])
+	case values["ProductName"] != "":
+		return strings.TrimSpace(values["ProductName"] + " " + values["ProductVersion"])
+	}
+	return ""
+}
+
+// parseDisks reads "df -P -k" output, keeping block devices and the root
+// filesystem.
+func parseDisks(lines []string) []Disk {
+	var disks []Disk
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[0] == "Filesystem" {
+			continue
+		}
+		mount := strings.Join(fields[5:], " ")
+		if !strings.HasPrefix(fields[0], "/dev/") && mount != "/" {
+			continue
+		}
+		size, err1 := strconv.ParseUint(fields[1], 10, 64)
+		used, err2 := strconv.ParseUint(fields[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		disks = append(disks, Disk{Mount: mount, SizeBytes: size * 1024, UsedBytes: used * 1024})
+	}
+	return disks
+}
+
+// FormatBytes renders n in binary units, e.g. "7.8 GiB".
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
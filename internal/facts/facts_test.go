@@ -0,0 +1,136 @@
+package facts
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+const linuxOutput = `==os==
+PRETTY_NAME="Ubuntu 22.04.4 LTS"
+NAME="Ubuntu"
+VERSION_ID="22.04"
+==kernel==
+Linux 5.15.0-105-generic
+==uptime==
+86400.52 340000.10
+==cpus==
+4
+==memory==
+MemTotal:        8148240 kB
+MemAvailable:    5120000 kB
+==disks==
+Filesystem     1024-blocks     Used Available Capacity Mounted on
+tmpfs               814824     1544    813280       1% /run
+/dev/sda1         50620216 25310108  25310108      50% /
+/dev/sdb1        103081248 10308124  92773124      10% /var/lib/data
+==addresses==
+10.0.0.5 172.17.0.1 fd00::5
+==end==
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse(linuxOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if f.OS != "Ubuntu 22.04.4 LTS" {
+		t.Errorf("Expected OS Ubuntu 22.04.4 LTS, got %q", f.OS)
+	}
+	if f.Kernel != "Linux 5.15.0-105-generic" {
+		t.Errorf("Expected kernel, got %q", f.Kernel)
+	}
+	if f.Uptime != 24*time.Hour {
+		t.Errorf("Expected uptime 24h, got %v", f.Uptime)
+	}
+	if f.CPUs != 4 {
+		t.Errorf("Expected 4 CPUs, got %d", f.CPUs)
+	}
+	if f.MemoryTotalBytes != 8148240*1024 || f.MemoryAvailableBytes != 5120000*1024 {
+		t.Errorf("Unexpected memory: %d total, %d available", f.MemoryTotalBytes, f.MemoryAvailableBytes)
+	}
+	if len(f.Disks) != 2 || f.Disks[0].Mount != "/" || f.Disks[0].UsedPercent() != 50 || f.Disks[1].Mount != "/var/lib/data" {
+		t.Errorf("Unexpected disks: %+v", f.Disks)
+	}
+	if strings.Join(f.Addresses, " ") != "10.0.0.5 172.17.0.1 fd00::5" {
+		t.Errorf("Unexpected addresses: %v", f.Addresses)
+	}
+	if f.Summary() != "Ubuntu 22.04.4 LTS, Linux 5.15.0-105-generic, 4 CPUs, 7.8 GiB" {
+		t.Errorf("Unexpected summary: %q", f.Summary())
+	}
+}
+
+func TestParse_Partial(t *testing.T) {
+	output := "==os==\nProductName:\tmacOS\nProductVersion:\t14.4\n==kernel==\nDarwin 23.4.0\n==uptime==\n==cpus==\n8\n==memory==\n==disks==\n==addresses==\n192.168.1.20/24\n==end==\n"
+	f, err := Parse(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.OS != "macOS 14.4" || f.Kernel != "Darwin 23.4.0" || f.CPUs != 8 || f.Uptime != 0 {
+		t.Errorf("Unexpected facts: %+v", f)
+	}
+	if len(f.Addresses) != 1 || f.Addresses[0] != "192.168.1.20" {
+		t.Errorf("Expected prefix length stripped, got %v", f.Addresses)
+	}
+}
+
+func TestParse_Truncated(t *testing.T) {
+	if _, err := Parse("==os==\nNAME=Debian\n"); err == nil {
+		t.Error("Expected error for output without end marker")
+	}
+	if _, err := Parse("bash: sh: command not found\n"); err == nil {
+		t.Error("Expected error for unrelated output")
+	}
+}
+
+func TestCommand_RunsLocally(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	out, err := exec.Command("sh", "-c", Command()).Output()
+	if err != nil {
+		t.Fatalf("Failed to run probe: %v", err)
+	}
+	f, err := Parse(string(out))
+	if err != nil {
+		t.Fatalf("Failed to parse probe output: %v\n%s", err, out)
+	}
+	if f.Kernel == "" {
+		t.Errorf("Expected a kernel, got %+v", f)
+	}
+}
+
+func TestStale(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	f := Facts{CollectedAt: now.Add(-2 * time.Hour)}
+
+	if f.Stale(0, now) {
+		t.Error("Expected zero max age to never expire")
+	}
+	if f.Stale(3*time.Hour, now) {
+		t.Error("Expected facts younger than max age to be fresh")
+	}
+	if !f.Stale(time.Hour, now) {
+		t.Error("Expected facts older than max age to be stale")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n        uint64
+		expected string
+	}{
+		{512, "512 B"},
+		{2048, "2.0 KiB"},
+		{8148240 * 1024, "7.8 GiB"},
+		{3 << 40, "3.0 TiB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.expected {
+			t.Errorf("FormatBytes(%d) = %q, expected %q", tt.n, got, tt.expected)
+		}
+	}
+}
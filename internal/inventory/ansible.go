@@ -0,0 +1,636 @@
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshcmd"
+	"github.com/omisai-tech/sshy/internal/sshconfig"
+	"gopkg.in/yaml.v3"
+)
+
+// AnsibleHost is a server as an Ansible inventory entry: its groups and the
+// connection variables.
+type AnsibleHost struct {
+	Name   string
+	Groups []string
+	Vars   []Var
+}
+
+// AnsibleHosts maps servers to Ansible hosts. Tags become groups and
+// servers without tags go to "ungrouped". Options and jump chains, resolved
+// against inventory, become ansible_ssh_common_args.
+func AnsibleHosts(servers, inventory models.Servers) ([]AnsibleHost, error) {
+	hosts := make([]AnsibleHost, 0, len(servers))
+	for _, s := range servers {
+		h := AnsibleHost{Name: s.Name, Groups: s.Tags}
+		if len(h.Groups) == 0 {
+			h.Groups = []string{"ungrouped"}
+		}
+		h.Vars = append(h.Vars, Var{"ansible_host", s.Host})
+		if s.User != "" {
+			h.Vars = append(h.Vars, Var{"ansible_user", s.User})
+		}
+		if s.Port != 0 {
+			h.Vars = append(h.Vars, Var{"ansible_port", strconv.Itoa(s.Port)})
+		}
+		if s.Key != "" {
+			h.Vars = append(h.Vars, Var{"ansible_ssh_private_key_file", s.Key})
+		}
+
+		args, err := sshconfig.OptionArgs(s.Options)
+		if err != nil {
+			return nil, fmt.Errorf("server %s: %w", s.Name, err)
+		}
+		target, err := sshcmd.NewTarget(s, inventory)
+		if err != nil {
+			return nil, err
+		}
+		jumpArgs, err := sshcmd.JumpArgs(target.Hops)
+		if err != nil {
+			return nil, err
+		}
+		if args = append(args, jumpArgs...); len(args) > 0 {
+			h.Vars = append(h.Vars, Var{"ansible_ssh_common_args", sshcmd.Join(args)})
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
+
+// groupOrder lists the groups of hosts in order of first appearance, each
+// with its members.
+func groupOrder(hosts []AnsibleHost) ([]string, map[string][]int) {
+	var order []string
+	members := make(map[string][]int)
+	for i, h := range hosts {
+		for _, g := range h.Groups {
+			if _, seen := members[g]; !seen {
+				order = append(order, g)
+			}
+			members[g] = append(members[g], i)
+		}
+	}
+	return order, members
+}
+
+// WriteAnsibleINI writes hosts as an INI inventory. A host's variables are
+// written on its first line only, as Ansible merges them across groups.
+func WriteAnsibleINI(w io.Writer, hosts []AnsibleHost) error {
+	order, members := groupOrder(hosts)
+	written := make(map[int]bool, len(hosts))
+	for i, g := range order {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "[%s]\n", g)
+		for _, idx := range members[g] {
+			line := hosts[idx].Name
+			if !written[idx] {
+				for _, v := range hosts[idx].Vars {
+					line += " " + v.Key + "=" + sshcmd.Quote(v.Value)
+				}
+				written[idx] = true
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteAnsibleYAML writes hosts as a YAML inventory under "all".
+func WriteAnsibleYAML(w io.Writer, hosts []AnsibleHost) error {
+	order, members := groupOrder(hosts)
+	written := make(map[int]bool, len(hosts))
+	children := &yaml.Node{Kind: yaml.MappingNode}
+	for _, g := range order {
+		groupHosts := &yaml.Node{Kind: yaml.MappingNode}
+		for _, idx := range members[g] {
+			vars := &yaml.Node{Kind: yaml.MappingNode}
+			if !written[idx] {
+				for _, v := range hosts[idx].Vars {
+					addNode(vars, v.Key, scalarNode(v.Key, v.Value))
+				}
+				written[idx] = true
+			}
+			addNode(groupHosts, hosts[idx].Name, vars)
+		}
+		group := &yaml.Node{Kind: yaml.MappingNode}
+		addNode(group, "hosts", groupHosts)
+		addNode(children, g, group)
+	}
+
+	all := &yaml.Node{Kind: yaml.MappingNode}
+	addNode(all, "children", children)
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	addNode(root, "all", all)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func addNode(mapping *yaml.Node, key string, value *yaml.Node) {
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+func scalarNode(key, value string) *yaml.Node {
+	if key == "ansible_port" {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// ansibleInventory is an inventory as read from INI or YAML, before group
+// membership and variables are resolved per host.
+type ansibleInventory struct {
+	hosts    []string
+	hostVars map[string]map[string]string
+	groups   map[string]*ansibleGroup
+	// order lists the groups in order of first appearance.
+	order []string
+}
+
+type ansibleGroup struct {
+	hosts    []string
+	children []string
+	vars     map[string]string
+}
+
+func newAnsibleInventory() *ansibleInventory {
+	return &ansibleInventory{
+		hostVars: make(map[string]map[string]string),
+		groups:   make(map[string]*ansibleGroup),
+	}
+}
+
+func (inv *ansibleInventory) group(name string) *ansibleGroup {
+	g, ok := inv.groups[name]
+	if !ok {
+		g = &ansibleGroup{vars: make(map[string]string)}
+		inv.groups[name] = g
+		inv.order = append(inv.order, name)
+	}
+	return g
+}
+
+func (inv *ansibleInventory) addHost(group, name string, vars map[string]string) {
+	if _, seen := inv.hostVars[name]; !seen {
+		inv.hosts = append(inv.hosts, name)
+		inv.hostVars[name] = make(map[string]string)
+	}
+	for k, v := range vars {
+		inv.hostVars[name][k] = v
+	}
+	g := inv.group(group)
+	for _, h := range g.hosts {
+		if h == name {
+			return
+		}
+	}
+	g.hosts = append(g.hosts, name)
+}
+
+// ParseAnsibleINI reads an INI inventory, expanding host ranges such as
+// web[01:03].example.com.
+func ParseAnsibleINI(r io.Reader) ([]Host, error) {
+	inv := newAnsibleInventory()
+	group, kind := "ungrouped", ""
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			group, kind = line[1:len(line)-1], ""
+			if i := strings.LastIndex(group, ":"); i >= 0 {
+				group, kind = group[:i], group[i+1:]
+			}
+			if kind != "" && kind != "vars" && kind != "children" {
+				return nil, fmt.Errorf("line %d: unknown section type %q", lineNo, kind)
+			}
+			inv.group(group)
+			continue
+		}
+
+		switch kind {
+		case "vars":
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key=value", lineNo)
+			}
+			inv.group(group).vars[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+		case "children":
+			g := inv.group(group)
+			g.children = append(g.children, line)
+			inv.group(line)
+		default:
+			fields, err := splitFields(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			vars := make(map[string]string)
+			for _, field := range fields[1:] {
+				key, value, ok := strings.Cut(field, "=")
+				if !ok {
+					return nil, fmt.Errorf("line %d: expected key=value, got %q", lineNo, field)
+				}
+				vars[key] = value
+			}
+			names, err := expandHostPattern(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			for _, name := range names {
+				inv.addHost(group, name, vars)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return inv.resolve(), nil
+}
+
+// ParseAnsibleYAML reads a YAML inventory. Top-level keys are groups,
+// usually just "all".
+func ParseAnsibleYAML(data []byte) ([]Host, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	inv := newAnsibleInventory()
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping of groups")
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if err := inv.parseYAMLGroup(root.Content[i].Value, root.Content[i+1]); err != nil {
+			return nil, err
+		}
+	}
+	return inv.resolve(), nil
+}
+
+func (inv *ansibleInventory) parseYAMLGroup(name string, node *yaml.Node) error {
+	g := inv.group(name)
+	if isNull(node) {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("group %s: expected a mapping", name)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if isNull(value) {
+			continue
+		}
+		if value.Kind != yaml.MappingNode {
+			return fmt.Errorf("group %s: %s must be a mapping", name, key)
+		}
+		switch key {
+		case "vars":
+			for k, v := range scalarVars(value) {
+				g.vars[k] = v
+			}
+		case "hosts":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				pattern, hostVars := value.Content[j].Value, value.Content[j+1]
+				if !isNull(hostVars) && hostVars.Kind != yaml.MappingNode {
+					return fmt.Errorf("host %s: expected a mapping", pattern)
+				}
+				expanded, err := expandHostPattern(pattern)
+				if err != nil {
+					return err
+				}
+				for _, host := range expanded {
+					inv.addHost(name, host, scalarVars(hostVars))
+				}
+			}
+		case "children":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				child := value.Content[j].Value
+				g.children = append(g.children, child)
+				if err := inv.parseYAMLGroup(child, value.Content[j+1]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node == nil || node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// scalarVars returns the scalar variables of a mapping. Structured variables
+// carry no connection settings and are left out.
+func scalarVars(node *yaml.Node) map[string]string {
+	vars := make(map[string]string)
+	if isNull(node) {
+		return vars
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if value := node.Content[i+1]; value.Kind == yaml.ScalarNode && !isNull(value) {
+			vars[node.Content[i].Value] = value.Value
+		}
+	}
+	return vars
+}
+
+// resolve turns the inventory into hosts in order of first appearance.
+// Every group a host is in, directly or through children, becomes a tag.
+// Variables are taken from "all", then from the farthest group to the
+// nearest, then from the host itself, later ones winning.
+func (inv *ansibleInventory) resolve() []Host {
+	parents := make(map[string][]string)
+	for _, name := range inv.order {
+		for _, child := range inv.groups[name].children {
+			parents[child] = append(parents[child], name)
+		}
+	}
+	direct := make(map[string][]string)
+	for _, name := range inv.order {
+		for _, host := range inv.groups[name].hosts {
+			direct[host] = append(direct[host], name)
+		}
+	}
+
+	hosts := make([]Host, 0, len(inv.hosts))
+	for _, name := range inv.hosts {
+		var groups []string
+		seen := make(map[string]bool)
+		queue := append([]string(nil), direct[name]...)
+		for len(queue) > 0 {
+			g := queue[0]
+			queue = queue[1:]
+			if seen[g] {
+				continue
+			}
+			seen[g] = true
+			groups = append(groups, g)
+			queue = append(queue, parents[g]...)
+		}
+
+		vars := make(map[string]string)
+		if all, ok := inv.groups["all"]; ok {
+			for k, v := range all.vars {
+				vars[k] = v
+			}
+		}
+		var tags []string
+		for i := len(groups) - 1; i >= 0; i-- {
+			for k, v := range inv.groups[groups[i]].vars {
+				vars[k] = v
+			}
+		}
+		for _, g := range groups {
+			if g != "all" && g != "ungrouped" {
+				tags = append(tags, g)
+			}
+		}
+		for k, v := range inv.hostVars[name] {
+			vars[k] = v
+		}
+
+		host := ansibleServer(name, vars)
+		host.Server.Tags = tags
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// ansibleServer maps the connection variables of a host to a server.
+func ansibleServer(name string, vars map[string]string) Host {
+	host := Host{Server: models.Server{Name: name, Host: name}}
+	s := &host.Server
+	var ignored []string
+	for _, key := range sortedKeys(vars) {
+		value := vars[key]
+		switch key {
+		case "ansible_host", "ansible_ssh_host":
+			s.Host = value
+		case "ansible_user", "ansible_ssh_user":
+			s.User = value
+		case "ansible_port", "ansible_ssh_port":
+			port, err := strconv.Atoi(value)
+			if err != nil || port <= 0 || port > 65535 {
+				host.Notes = append(host.Notes, fmt.Sprintf("invalid port %q ignored", value))
+				continue
+			}
+			s.Port = port
+		case "ansible_ssh_private_key_file":
+			s.Key = value
+		case "ansible_ssh_common_args", "ansible_ssh_extra_args":
+			host.Notes = append(host.Notes, applySSHArgs(s, value)...)
+		default:
+			if strings.HasPrefix(key, "ansible_") {
+				ignored = append(ignored, key)
+			}
+		}
+	}
+	if len(ignored) > 0 {
+		host.Notes = append(host.Notes, "not imported: "+strings.Join(ignored, ", "))
+	}
+	return host
+}
+
+// applySSHArgs reads -o, -J and -i from ssh arguments into the server.
+func applySSHArgs(s *models.Server, value string) []string {
+	fields, err := splitFields(value)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot parse ssh arguments %q: %v", value, err)}
+	}
+	var notes []string
+	for i := 0; i < len(fields); i++ {
+		flag, arg := fields[i], ""
+		if len(flag) > 2 && strings.HasPrefix(flag, "-") {
+			flag, arg = fields[i][:2], fields[i][2:]
+		} else if i+1 < len(fields) {
+			arg = fields[i+1]
+			i++
+		}
+		switch flag {
+		case "-o":
+			key, val, ok := strings.Cut(arg, "=")
+			if !ok {
+				key, val, _ = strings.Cut(arg, " ")
+			}
+			canonical, known := sshconfig.CanonicalKeyword(strings.TrimSpace(key))
+			if !known {
+				notes = append(notes, fmt.Sprintf("unknown ssh option %q ignored", key))
+				continue
+			}
+			if s.Options == nil {
+				s.Options = make(map[string]interface{})
+			}
+			s.Options[canonical] = strings.TrimSpace(val)
+		case "-J":
+			s.Jump = models.SplitJumpChain(arg)
+		case "-i":
+			if s.Key == "" {
+				s.Key = arg
+			}
+		default:
+			notes = append(notes, fmt.Sprintf("ssh argument %s ignored", fields[i]))
+		}
+	}
+	return notes
+}
+
+// expandHostPattern expands Ansible host ranges: [1:3], [01:10:2] and
+// [a:c]. Several ranges in one pattern multiply.
+func expandHostPattern(pattern string) ([]string, error) {
+	open := strings.Index(pattern, "[")
+	if open < 0 {
+		return []string{pattern}, nil
+	}
+	end := strings.Index(pattern[open:], "]")
+	if end < 0 {
+		return nil, fmt.Errorf("unclosed range in %q", pattern)
+	}
+	end += open
+	prefix, spec, rest := pattern[:open], pattern[open+1:end], pattern[end+1:]
+
+	values, err := expandRange(spec)
+	if err != nil {
+		return nil, fmt.Errorf("host %q: %w", pattern, err)
+	}
+	suffixes, err := expandHostPattern(rest)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, v := range values {
+		for _, suffix := range suffixes {
+			names = append(names, prefix+v+suffix)
+		}
+	}
+	return names, nil
+}
+
+func expandRange(spec string) ([]string, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid range [%s]", spec)
+	}
+	step := 1
+	if len(parts) == 3 {
+		var err error
+		if step, err = strconv.Atoi(parts[2]); err != nil || step <= 0 {
+			return nil, fmt.Errorf("invalid range step %q", parts[2])
+		}
+	}
+
+	start, end := parts[0], parts[1]
+	if len(start) == 1 && len(end) == 1 && isLetter(start[0]) && isLetter(end[0]) {
+		if start > end {
+			return nil, fmt.Errorf("invalid range [%s]", spec)
+		}
+		var values []string
+		for c := int(start[0]); c <= int(end[0]); c += step {
+			values = append(values, string(rune(c)))
+		}
+		return values, nil
+	}
+
+	from, err1 := strconv.Atoi(start)
+	to, err2 := strconv.Atoi(end)
+	if err1 != nil || err2 != nil || from > to {
+		return nil, fmt.Errorf("invalid range [%s]", spec)
+	}
+	width := 0
+	if len(start) > 1 && start[0] == '0' {
+		width = len(start)
+	}
+	var values []string
+	for n := from; n <= to; n += step {
+		values = append(values, fmt.Sprintf("%0*d", width, n))
+	}
+	return values, nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// splitFields splits a line into shell-style words, honouring single and
+// double quotes and backslash escapes.
+func splitFields(line string) ([]string, error) {
+	var fields []string
+	var current bytes.Buffer
+	inField := false
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(line) {
+				i++
+				current.WriteByte(line[i])
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inField = c, true
+		case c == '\\' && i+1 < len(line):
+			i++
+			current.WriteByte(line[i])
+			inField = true
+		case c == ' ' || c == '\t':
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteByte(c)
+			inField = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,199 @@
+package inventory
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestExpandHostPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected []string
+		wantErr  bool
+	}{
+		{"web", []string{"web"}, false},
+		{"web[1:3]", []string{"web1", "web2", "web3"}, false},
+		{"web[01:03].example.com", []string{"web01.example.com", "web02.example.com", "web03.example.com"}, false},
+		{"db-[a:c]", []string{"db-a", "db-b", "db-c"}, false},
+		{"n[0:6:3]", []string{"n0", "n3", "n6"}, false},
+		{"r[1:2]-[a:b]", []string{"r1-a", "r1-b", "r2-a", "r2-b"}, false},
+		{"web[3:1]", nil, true},
+		{"web[1:", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			result, err := expandHostPattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+const iniInventory = `# production
+bastion ansible_host=203.0.113.10 ansible_user=ops
+
+[web]
+web[1:2] ansible_host=10.0.0.1 ansible_ssh_common_args='-o ForwardAgent=yes -J bastion'
+
+[db]
+db-1 ansible_host=10.0.1.1 ansible_port=2222 ansible_ssh_pass=secret
+
+[prod:children]
+web
+db
+
+[prod:vars]
+ansible_user=deploy
+
+[all:vars]
+ansible_user=root
+ansible_ssh_private_key_file=~/.ssh/prod
+`
+
+func TestParseAnsibleINI(t *testing.T) {
+	hosts, err := ParseAnsibleINI(strings.NewReader(iniInventory))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(hosts) != 4 {
+		t.Fatalf("Expected 4 hosts, got %d", len(hosts))
+	}
+
+	expected := []models.Server{
+		{Name: "bastion", Host: "203.0.113.10", User: "ops", Key: "~/.ssh/prod"},
+		{Name: "web1", Host: "10.0.0.1", User: "deploy", Key: "~/.ssh/prod", Tags: []string{"web", "prod"},
+			Jump: models.JumpChain{"bastion"}, Options: map[string]interface{}{"ForwardAgent": "yes"}},
+		{Name: "web2", Host: "10.0.0.1", User: "deploy", Key: "~/.ssh/prod", Tags: []string{"web", "prod"},
+			Jump: models.JumpChain{"bastion"}, Options: map[string]interface{}{"ForwardAgent": "yes"}},
+		{Name: "db-1", Host: "10.0.1.1", User: "deploy", Port: 2222, Key: "~/.ssh/prod", Tags: []string{"db", "prod"}},
+	}
+	for i, want := range expected {
+		if !reflect.DeepEqual(hosts[i].Server, want) {
+			t.Errorf("Expected %+v, got %+v", want, hosts[i].Server)
+		}
+	}
+	if len(hosts[3].Notes) != 1 || hosts[3].Notes[0] != "not imported: ansible_ssh_pass" {
+		t.Errorf("Expected a note about ansible_ssh_pass, got %v", hosts[3].Notes)
+	}
+}
+
+const yamlInventory = `all:
+  vars:
+    ansible_user: root
+  hosts:
+    bastion:
+      ansible_host: 203.0.113.10
+  children:
+    prod:
+      vars:
+        ansible_user: deploy
+      children:
+        web:
+          hosts:
+            web[1:2]:
+        db:
+          hosts:
+            db-1:
+              ansible_host: 10.0.1.1
+              ansible_port: 2222
+              tuning:
+                shared_buffers: 1GB
+`
+
+func TestParseAnsibleYAML(t *testing.T) {
+	hosts, err := ParseAnsibleYAML([]byte(yamlInventory))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []models.Server{
+		{Name: "bastion", Host: "203.0.113.10", User: "root"},
+		{Name: "web1", Host: "web1", User: "deploy", Tags: []string{"web", "prod"}},
+		{Name: "web2", Host: "web2", User: "deploy", Tags: []string{"web", "prod"}},
+		{Name: "db-1", Host: "10.0.1.1", User: "deploy", Port: 2222, Tags: []string{"db", "prod"}},
+	}
+	if len(hosts) != len(expected) {
+		t.Fatalf("Expected %d hosts, got %d", len(expected), len(hosts))
+	}
+	for i, want := range expected {
+		if !reflect.DeepEqual(hosts[i].Server, want) {
+			t.Errorf("Expected %+v, got %+v", want, hosts[i].Server)
+		}
+	}
+}
+
+func TestWriteAnsible_RoundTrip(t *testing.T) {
+	servers := models.Servers{
+		{Name: "bastion", Host: "203.0.113.10", User: "ops"},
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Port: 2222, Key: "~/.ssh/web", Tags: []string{"web", "prod"},
+			Options: map[string]interface{}{"ForwardAgent": true}},
+	}
+	hosts, err := AnsibleHosts(servers, servers)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ini bytes.Buffer
+	if err := WriteAnsibleINI(&ini, hosts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectedINI := `[ungrouped]
+bastion ansible_host=203.0.113.10 ansible_user=ops
+
+[web]
+web-1 ansible_host=10.0.0.1 ansible_user=deploy ansible_port=2222 ansible_ssh_private_key_file='~/.ssh/web' ansible_ssh_common_args='-o ForwardAgent=yes'
+
+[prod]
+web-1
+`
+	if ini.String() != expectedINI {
+		t.Errorf("Expected INI:\n%s\ngot:\n%s", expectedINI, ini.String())
+	}
+
+	var yml bytes.Buffer
+	if err := WriteAnsibleYAML(&yml, hosts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for name, parse := range map[string]func() ([]Host, error){
+		"ini":  func() ([]Host, error) { return ParseAnsibleINI(&ini) },
+		"yaml": func() ([]Host, error) { return ParseAnsibleYAML(yml.Bytes()) },
+	} {
+		parsed, err := parse()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", name, err)
+		}
+		if len(parsed) != 2 {
+			t.Fatalf("%s: Expected 2 hosts, got %d", name, len(parsed))
+		}
+		want := servers[1]
+		want.Options = map[string]interface{}{"ForwardAgent": "yes"}
+		if !reflect.DeepEqual(parsed[1].Server, want) {
+			t.Errorf("%s: Expected %+v, got %+v", name, want, parsed[1].Server)
+		}
+	}
+}
+
+func TestAnsibleHosts_Jump(t *testing.T) {
+	servers := models.Servers{
+		{Name: "bastion", Host: "203.0.113.10", User: "ops"},
+		{Name: "db", Host: "10.0.1.1", Jump: models.JumpChain{"bastion"}},
+	}
+	hosts, err := AnsibleHosts(servers[1:], servers)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	last := hosts[0].Vars[len(hosts[0].Vars)-1]
+	if last.Key != "ansible_ssh_common_args" || !strings.Contains(last.Value, "ops@203.0.113.10") {
+		t.Errorf("Expected the jump chain in ansible_ssh_common_args, got %v", last)
+	}
+}
@@ -0,0 +1,293 @@
+package inventory
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshconfig"
+)
+
+// CSVFields are the server fields that map to CSV columns, in export order.
+var CSVFields = []string{"name", "host", "user", "port", "key", "tags", "jump"}
+
+// csvOptionPrefix starts the header of a column holding an SSH option.
+const csvOptionPrefix = "option."
+
+// CSVOptions configures how servers map to CSV.
+type CSVOptions struct {
+	// Columns maps server fields to column headers. Fields that are not
+	// mapped use the field name as header.
+	Columns map[string]string
+	// Delimiter separates tags, jump hosts and repeated option values
+	// inside one cell.
+	Delimiter string
+}
+
+// ParseCSVColumns reads field=Header mappings.
+func ParseCSVColumns(specs []string) (map[string]string, error) {
+	columns := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		field, header, ok := strings.Cut(spec, "=")
+		field, header = strings.ToLower(strings.TrimSpace(field)), strings.TrimSpace(header)
+		if !ok || header == "" {
+			return nil, fmt.Errorf("invalid column mapping %q: use field=Header", spec)
+		}
+		if !isCSVField(field) {
+			return nil, fmt.Errorf("unknown field %q in column mapping: use one of %s", field, strings.Join(CSVFields, ", "))
+		}
+		columns[field] = header
+	}
+	return columns, nil
+}
+
+func isCSVField(field string) bool {
+	for _, f := range CSVFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (o CSVOptions) header(field string) string {
+	if h, ok := o.Columns[field]; ok {
+		return h
+	}
+	return field
+}
+
+func (o CSVOptions) delimiter() string {
+	if o.Delimiter == "" {
+		return ";"
+	}
+	return o.Delimiter
+}
+
+// ParseCSV reads servers from CSV with a header row. The name and host
+// columns are required; columns named option.<Keyword> hold SSH options and
+// other columns are ignored. Every invalid row is reported, by its number
+// in the file with the header as row 1.
+func ParseCSV(r io.Reader, opts CSVOptions) ([]Host, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fieldIndex := make(map[string]int)
+	optionIndex := make(map[string]int)
+	var optionKeys []string
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		for _, field := range CSVFields {
+			if strings.EqualFold(h, opts.header(field)) {
+				fieldIndex[field] = i
+			}
+		}
+		if len(h) > len(csvOptionPrefix) && strings.EqualFold(h[:len(csvOptionPrefix)], csvOptionPrefix) {
+			key, ok := sshconfig.CanonicalKeyword(h[len(csvOptionPrefix):])
+			if !ok {
+				return nil, fmt.Errorf("column %q: unknown ssh option", h)
+			}
+			optionIndex[key] = i
+			optionKeys = append(optionKeys, key)
+		}
+	}
+	for _, field := range []string{"name", "host"} {
+		if _, ok := fieldIndex[field]; !ok {
+			return nil, fmt.Errorf("missing column %q", opts.header(field))
+		}
+	}
+
+	var hosts []Host
+	var errs []error
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		cell := func(index int, ok bool) string {
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		field := func(name string) string {
+			index, ok := fieldIndex[name]
+			return cell(index, ok)
+		}
+
+		s := models.Server{
+			Name: field("name"),
+			Host: field("host"),
+			User: field("user"),
+			Key:  field("key"),
+			Tags: splitCell(field("tags"), opts.delimiter()),
+		}
+		if jump := field("jump"); jump != "" {
+			s.Jump = splitCell(jump, opts.delimiter())
+		}
+		if s.Name == "" {
+			errs = append(errs, fmt.Errorf("row %d: missing name", row))
+			continue
+		}
+		if s.Host == "" {
+			errs = append(errs, fmt.Errorf("row %d: %s: missing host", row, s.Name))
+			continue
+		}
+		if port := field("port"); port != "" {
+			n, err := strconv.Atoi(port)
+			if err != nil || n < 1 || n > 65535 {
+				errs = append(errs, fmt.Errorf("row %d: %s: invalid port %q", row, s.Name, port))
+				continue
+			}
+			s.Port = n
+		}
+		for _, key := range optionKeys {
+			value := cell(optionIndex[key], true)
+			if value == "" {
+				continue
+			}
+			if s.Options == nil {
+				s.Options = make(map[string]interface{})
+			}
+			values := []string{value}
+			if sshconfig.MultiValued(key) {
+				values = splitCell(value, opts.delimiter())
+			}
+			if len(values) == 1 {
+				s.Options[key] = values[0]
+				continue
+			}
+			list := make([]interface{}, len(values))
+			for i, v := range values {
+				list[i] = v
+			}
+			s.Options[key] = list
+		}
+		hosts = append(hosts, Host{Server: s})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return hosts, nil
+}
+
+// WriteCSV writes servers with their source, as [S], [L] or [O] in the
+// first column, followed by the server fields and one option.<Keyword>
+// column per option set on any server. Shared servers of a named source
+// show it as [S:name], like list does. ProxyJump is written to the jump
+// column only.
+func WriteCSV(w io.Writer, servers []models.ServerWithSource, opts CSVOptions) error {
+	optionSet := make(map[string]bool)
+	parsed := make([][]sshconfig.Option, len(servers))
+	for i, sws := range servers {
+		options, err := sshconfig.ParseOptions(sws.Server.Options)
+		if err != nil {
+			return fmt.Errorf("server %s: %w", sws.Server.Name, err)
+		}
+		parsed[i] = options
+		for _, o := range options {
+			optionSet[o.Key] = true
+		}
+	}
+	optionKeys := make([]string, 0, len(optionSet))
+	for k := range optionSet {
+		optionKeys = append(optionKeys, k)
+	}
+	sort.Strings(optionKeys)
+
+	writer := csv.NewWriter(w)
+	header := []string{"source"}
+	for _, field := range CSVFields {
+		header = append(header, opts.header(field))
+	}
+	for _, k := range optionKeys {
+		header = append(header, csvOptionPrefix+k)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for i, sws := range servers {
+		s := sws.Server
+		port := ""
+		if s.Port != 0 {
+			port = strconv.Itoa(s.Port)
+		}
+		record := []string{
+			sourceFlag(sws),
+			s.Name,
+			s.Host,
+			s.User,
+			port,
+			s.Key,
+			strings.Join(s.Tags, opts.delimiter()),
+			strings.Join(s.JumpHops(), opts.delimiter()),
+		}
+		values := make(map[string][]string)
+		for _, o := range parsed[i] {
+			values[o.Key] = append(values[o.Key], o.Value)
+		}
+		for _, k := range optionKeys {
+			record = append(record, strings.Join(values[k], opts.delimiter()))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func sourceFlag(sws models.ServerWithSource) string {
+	switch sws.Source {
+	case models.SourceShared:
+		if sws.Origin != "" {
+			return "[S:" + sws.Origin + "]"
+		}
+		return "[S]"
+	case models.SourceLocal:
+		return "[L]"
+	case models.SourceOverride:
+		return "[O]"
+	}
+	return ""
+}
+
+func splitCell(value, delimiter string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, delimiter) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package inventory
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := `Hostname,IP,user,port,Groups,option.ForwardAgent,option.localforward,notes
+web-1,10.0.0.1,deploy,2222,web|prod,yes,8080:localhost:80|9090:localhost:90,frontend
+,,,,,,,
+db-1,10.0.1.1,,,db,,,
+`
+	opts := CSVOptions{
+		Columns:   map[string]string{"name": "Hostname", "host": "IP", "tags": "Groups"},
+		Delimiter: "|",
+	}
+	hosts, err := ParseCSV(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []models.Server{
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Port: 2222, Tags: []string{"web", "prod"}, Options: map[string]interface{}{
+			"ForwardAgent": "yes",
+			"LocalForward": []interface{}{"8080:localhost:80", "9090:localhost:90"},
+		}},
+		{Name: "db-1", Host: "10.0.1.1", Tags: []string{"db"}},
+	}
+	if len(hosts) != len(expected) {
+		t.Fatalf("Expected %d hosts, got %d", len(expected), len(hosts))
+	}
+	for i, want := range expected {
+		if !reflect.DeepEqual(hosts[i].Server, want) {
+			t.Errorf("Expected %+v, got %+v", want, hosts[i].Server)
+		}
+	}
+}
+
+func TestParseCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "missing column",
+			input:    "name,user\nweb,deploy\n",
+			expected: []string{`missing column "host"`},
+		},
+		{
+			name:     "unknown option",
+			input:    "name,host,option.ForwardAgnet\nweb,10.0.0.1,yes\n",
+			expected: []string{`column "option.ForwardAgnet": unknown ssh option`},
+		},
+		{
+			name:     "invalid rows",
+			input:    "name,host,port\nweb,10.0.0.1,22\n,10.0.0.2,\ndb,,\ncache,10.0.0.4,http\n",
+			expected: []string{"row 3: missing name", "row 4: db: missing host", `row 5: cache: invalid port "http"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(tt.input), CSVOptions{})
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSVColumns(t *testing.T) {
+	columns, err := ParseCSVColumns([]string{"name=Server", " Host = IP address "})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := map[string]string{"name": "Server", "host": "IP address"}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected %v, got %v", expected, columns)
+	}
+
+	for _, spec := range []string{"name", "address=IP", "name="} {
+		if _, err := ParseCSVColumns([]string{spec}); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	servers := []models.ServerWithSource{
+		{Server: models.Server{Name: "web-1", Host: "10.0.0.1", User: "deploy", Tags: []string{"web", "prod"},
+			Options: map[string]interface{}{"ForwardAgent": true}}, Source: models.SourceShared},
+		{Server: models.Server{Name: "db-1", Host: "10.0.1.1", Port: 2222, Jump: models.JumpChain{"web-1"}}, Source: models.SourceOverride},
+		{Server: models.Server{Name: "lab", Host: "192.168.1.5", Key: "~/.ssh/lab"}, Source: models.SourceLocal},
+		{Server: models.Server{Name: "ci", Host: "10.1.0.2", Options: map[string]interface{}{"ProxyJump": "web-1"}},
+			Source: models.SourceShared, Origin: "platform"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, servers, CSVOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `source,name,host,user,port,key,tags,jump,option.ForwardAgent
+[S],web-1,10.0.0.1,deploy,,,web;prod,,yes
+[O],db-1,10.0.1.1,,2222,,,web-1,
+[L],lab,192.168.1.5,,,~/.ssh/lab,,,
+[S:platform],ci,10.1.0.2,,,,,web-1,
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	hosts, err := ParseCSV(&buf, CSVOptions{})
+	if err != nil {
+		t.Fatalf("Expected the export to import again, got %v", err)
+	}
+	if len(hosts) != 4 || hosts[1].Server.Port != 2222 || hosts[1].Server.Jump[0] != "web-1" {
+		t.Errorf("Expected the servers back, got %+v", hosts)
+	}
+}
@@ -0,0 +1,17 @@
+// Package inventory reads and writes the server lists of other tools.
+package inventory
+
+import "github.com/omisai-tech/sshy/internal/models"
+
+// Host is a server read from another tool, with notes about settings that
+// could not be carried over.
+type Host struct {
+	Server models.Server
+	Notes  []string
+}
+
+// Var is a named value, kept in a slice where the order matters.
+type Var struct {
+	Key   string
+	Value string
+}
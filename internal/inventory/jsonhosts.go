@@ -0,0 +1,194 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// jsonFields lists, per server field, the attribute paths tried in order in
+// a host object. They cover Termius exports and the usual spellings of
+// other tools.
+var jsonFields = map[string][]string{
+	"name": {"label", "name", "alias", "title"},
+	"host": {"address", "host", "hostname", "ip"},
+	"port": {"port", "ssh_config.port"},
+	"user": {"username", "user", "ssh_config.identity.username", "identity.username"},
+	"key":  {"identity_file", "key_file", "private_key_file", "key"},
+	"jump": {"jump", "proxy_jump", "jump_host", "jump_hosts"},
+	"tags": {"tags", "groups", "group.label", "group.name", "group"},
+}
+
+// jsonMetadata lists attributes that carry no connection settings and are
+// not worth a note.
+var jsonMetadata = map[string]bool{
+	"id": true, "uuid": true, "local_id": true, "created_at": true, "updated_at": true,
+	"color": true, "icon": true, "description": true, "notes": true,
+}
+
+// ParseJSONHosts reads hosts from a JSON export: either a list of host
+// objects or an object with such a list under "hosts". Termius exports and
+// similar tools are supported through the attribute names in jsonFields.
+// Hosts without an address are returned as skipped.
+func ParseJSONHosts(data []byte) (hosts []Host, skipped []string, err error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if obj, ok := doc.(map[string]interface{}); ok {
+		doc = obj["hosts"]
+	}
+	list, ok := doc.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("expected a list of hosts or an object with a \"hosts\" list")
+	}
+
+	for i, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("host %d: expected an object", i+1)
+		}
+		h, ok := jsonHost(obj)
+		if !ok {
+			label := h.Server.Name
+			if label == "" {
+				label = fmt.Sprintf("host %d", i+1)
+			}
+			skipped = append(skipped, label+": no address")
+			continue
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, skipped, nil
+}
+
+func jsonHost(obj map[string]interface{}) (Host, bool) {
+	used := make(map[string]bool)
+	field := func(name string) interface{} {
+		for _, path := range jsonFields[name] {
+			if value := lookupPath(obj, path); value != nil {
+				used[strings.SplitN(path, ".", 2)[0]] = true
+				return value
+			}
+		}
+		return nil
+	}
+	str := func(name string) string {
+		switch v := field(name).(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return ""
+	}
+
+	var h Host
+	s := &h.Server
+	s.Host = str("host")
+	s.Name = str("name")
+	if s.Name == "" {
+		s.Name = s.Host
+	}
+	if s.Host == "" {
+		return h, false
+	}
+	s.User = str("user")
+	if port := str("port"); port != "" {
+		if n, err := strconv.Atoi(port); err == nil && n > 0 && n <= 65535 {
+			if n != 22 {
+				s.Port = n
+			}
+		} else {
+			h.Notes = append(h.Notes, fmt.Sprintf("invalid port %q ignored", port))
+		}
+	}
+	s.Key = str("key")
+
+	switch v := field("jump").(type) {
+	case string:
+		s.Jump = models.SplitJumpChain(v)
+	case []interface{}:
+		s.Jump = stringList(v)
+	}
+	switch v := field("tags").(type) {
+	case string:
+		s.Tags = []string{tagName(v)}
+	case []interface{}:
+		for _, tag := range stringList(v) {
+			s.Tags = append(s.Tags, tagName(tag))
+		}
+	}
+
+	if _, vaultKey := obj["ssh_key"]; vaultKey && s.Key == "" {
+		h.Notes = append(h.Notes, "key stored in the app's vault not imported; export it to a file and set key")
+		used["ssh_key"] = true
+	}
+
+	var lost []string
+	for _, key := range sortedKeys(obj) {
+		if used[key] || jsonMetadata[key] || isEmptyValue(obj[key]) {
+			continue
+		}
+		lost = append(lost, key)
+	}
+	sort.Strings(lost)
+	if len(lost) > 0 {
+		h.Notes = append(h.Notes, "not imported: "+strings.Join(lost, ", "))
+	}
+	return h, true
+}
+
+// lookupPath resolves a dotted path through nested objects.
+func lookupPath(obj map[string]interface{}, path string) interface{} {
+	var value interface{} = obj
+	for _, part := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+	if isEmptyValue(value) {
+		return nil
+	}
+	return value
+}
+
+func stringList(values []interface{}) []string {
+	var result []string
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			result = append(result, v)
+		case map[string]interface{}:
+			// Lists of groups or hosts may hold objects with a label.
+			if label, ok := v["label"].(string); ok {
+				result = append(result, label)
+			} else if name, ok := v["name"].(string); ok {
+				result = append(result, name)
+			}
+		}
+	}
+	return result
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
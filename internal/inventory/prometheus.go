@@ -0,0 +1,89 @@
+package inventory
+
+import (
+	"encoding/json"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// TargetGroup is an entry of a Prometheus file_sd document.
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// PrometheusTargets groups servers into file_sd target groups at host:port.
+// Servers with the same tags and source share a group, labelled with
+// sshy_source, sshy_tags (comma-separated and comma-enclosed, so a tag can
+// be matched with a regex like ".*,prod,.*") and one sshy_tag_<tag> label
+// set to "true" per tag. With perServer, every server gets its own group
+// that also carries sshy_name.
+func PrometheusTargets(servers []models.ServerWithSource, port int, perServer bool) []TargetGroup {
+	var groups []TargetGroup
+	index := make(map[string]int)
+	for _, sws := range servers {
+		s := sws.Server
+		tags := append([]string(nil), s.Tags...)
+		sort.Strings(tags)
+
+		key := sourceName(sws.Source) + "\x00" + strings.Join(tags, "\x00")
+		if perServer {
+			key = s.Name
+		}
+		target := net.JoinHostPort(s.Host, strconv.Itoa(port))
+		if i, ok := index[key]; ok {
+			groups[i].Targets = append(groups[i].Targets, target)
+			continue
+		}
+
+		labels := map[string]string{"sshy_source": sourceName(sws.Source)}
+		if len(tags) > 0 {
+			labels["sshy_tags"] = "," + strings.Join(tags, ",") + ","
+		}
+		for _, tag := range tags {
+			labels["sshy_tag_"+labelName(tag)] = "true"
+		}
+		if perServer {
+			labels["sshy_name"] = s.Name
+		}
+		index[key] = len(groups)
+		groups = append(groups, TargetGroup{Targets: []string{target}, Labels: labels})
+	}
+	return groups
+}
+
+// MarshalTargets renders target groups as an indented file_sd document.
+func MarshalTargets(groups []TargetGroup) ([]byte, error) {
+	if groups == nil {
+		groups = []TargetGroup{}
+	}
+	data, err := json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func sourceName(source models.ServerSource) string {
+	switch source {
+	case models.SourceLocal:
+		return "local"
+	case models.SourceOverride:
+		return "override"
+	}
+	return "shared"
+}
+
+// labelName turns a tag into a valid Prometheus label name suffix.
+func labelName(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, tag)
+}
@@ -0,0 +1,59 @@
+package inventory
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestPrometheusTargets(t *testing.T) {
+	servers := []models.ServerWithSource{
+		{Server: models.Server{Name: "web-1", Host: "10.0.0.1", Tags: []string{"web", "prod"}}, Source: models.SourceShared},
+		{Server: models.Server{Name: "web-2", Host: "10.0.0.2", Tags: []string{"prod", "web"}}, Source: models.SourceShared},
+		{Server: models.Server{Name: "web-3", Host: "10.0.0.3", Tags: []string{"web", "prod"}}, Source: models.SourceOverride},
+		{Server: models.Server{Name: "lab", Host: "fe80::1", Tags: []string{"eu-west"}}, Source: models.SourceLocal},
+		{Server: models.Server{Name: "bare", Host: "10.0.0.9"}, Source: models.SourceShared},
+	}
+
+	groups := PrometheusTargets(servers, 9100, false)
+	expected := []TargetGroup{
+		{Targets: []string{"10.0.0.1:9100", "10.0.0.2:9100"}, Labels: map[string]string{
+			"sshy_source": "shared", "sshy_tags": ",prod,web,", "sshy_tag_prod": "true", "sshy_tag_web": "true",
+		}},
+		{Targets: []string{"10.0.0.3:9100"}, Labels: map[string]string{
+			"sshy_source": "override", "sshy_tags": ",prod,web,", "sshy_tag_prod": "true", "sshy_tag_web": "true",
+		}},
+		{Targets: []string{"[fe80::1]:9100"}, Labels: map[string]string{
+			"sshy_source": "local", "sshy_tags": ",eu-west,", "sshy_tag_eu_west": "true",
+		}},
+		{Targets: []string{"10.0.0.9:9100"}, Labels: map[string]string{"sshy_source": "shared"}},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, groups)
+	}
+
+	perServer := PrometheusTargets(servers, 9100, true)
+	if len(perServer) != len(servers) {
+		t.Fatalf("Expected one group per server, got %d", len(perServer))
+	}
+	if perServer[1].Labels["sshy_name"] != "web-2" {
+		t.Errorf("Expected sshy_name web-2, got %v", perServer[1].Labels)
+	}
+}
+
+func TestMarshalTargets(t *testing.T) {
+	data, err := MarshalTargets(nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != "[]\n" {
+		t.Errorf("Expected an empty list, got %q", data)
+	}
+
+	data, _ = MarshalTargets([]TargetGroup{{Targets: []string{"10.0.0.1:9100"}}})
+	if !strings.Contains(string(data), `"targets": [`) || strings.Contains(string(data), "labels") {
+		t.Errorf("Expected targets without labels, got %s", data)
+	}
+}
@@ -0,0 +1,205 @@
+package inventory
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// puttyNoted lists the PuTTY settings whose loss is worth mentioning when
+// they are set.
+var puttyNoted = []string{
+	"RemoteCommand",
+	"X11Forward",
+	"LocalUserName",
+	"GSSAPIFwd",
+}
+
+// ParsePuttySessions reads PuTTY sessions as stored on Linux, one file per
+// session named after the URL-encoded session name. path is the sessions
+// directory or a single session file. "Default Settings" is skipped, and
+// sessions that are not SSH are returned as skipped with the reason.
+func ParsePuttySessions(path string) (hosts []Host, skipped []string, err error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, nil, err
+	} else if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = files[:0]
+		for _, e := range entries {
+			if !e.IsDir() {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+
+	for _, file := range files {
+		name, err := url.PathUnescape(filepath.Base(file))
+		if err != nil {
+			name = filepath.Base(file)
+		}
+		if name == "Default Settings" {
+			continue
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		settings, err := readPuttySettings(f)
+		f.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if protocol := settings["Protocol"]; protocol != "" && protocol != "ssh" {
+			skipped = append(skipped, fmt.Sprintf("%s: protocol %s", name, protocol))
+			continue
+		}
+		host, ok := puttyHost(name, settings)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("%s: no host name", name))
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, skipped, nil
+}
+
+func readPuttySettings(r io.Reader) (map[string]string, error) {
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			settings[key] = value
+		}
+	}
+	return settings, scanner.Err()
+}
+
+func puttyHost(name string, settings map[string]string) (Host, bool) {
+	h := Host{Server: models.Server{Name: name}}
+	s := &h.Server
+
+	hostName := settings["HostName"]
+	if user, host, ok := strings.Cut(hostName, "@"); ok {
+		s.User, hostName = user, host
+	}
+	if hostName == "" {
+		return h, false
+	}
+	s.Host = hostName
+	if user := settings["UserName"]; user != "" {
+		s.User = user
+	}
+	if port, err := strconv.Atoi(settings["PortNumber"]); err == nil && port != 22 && port > 0 {
+		s.Port = port
+	}
+
+	if key := settings["PublicKeyFile"]; key != "" {
+		if strings.HasSuffix(strings.ToLower(key), ".ppk") {
+			h.Notes = append(h.Notes, fmt.Sprintf("key %s is in PuTTY format; convert it with \"puttygen %s -O private-openssh -o KEY\" and set key", key, key))
+		} else {
+			s.Key = key
+		}
+	}
+
+	options := make(map[string]interface{})
+	if settings["AgentFwd"] == "1" {
+		options["ForwardAgent"] = "yes"
+	}
+	if settings["Compression"] == "1" {
+		options["Compression"] = "yes"
+	}
+	for _, forward := range strings.Split(settings["PortForwardings"], ",") {
+		if forward == "" {
+			continue
+		}
+		key, value, note := puttyForward(forward)
+		if note != "" {
+			h.Notes = append(h.Notes, note)
+			continue
+		}
+		if existing, ok := options[key].([]interface{}); ok {
+			options[key] = append(existing, value)
+		} else {
+			options[key] = []interface{}{value}
+		}
+	}
+	if len(options) > 0 {
+		s.Options = options
+	}
+
+	h.Notes = append(h.Notes, puttyProxy(s, settings)...)
+
+	var lost []string
+	for _, key := range puttyNoted {
+		if v := settings[key]; v != "" && v != "0" {
+			lost = append(lost, key)
+		}
+	}
+	sort.Strings(lost)
+	if len(lost) > 0 {
+		h.Notes = append(h.Notes, "not imported: "+strings.Join(lost, ", "))
+	}
+	return h, true
+}
+
+// puttyForward converts a PuTTY forwarding such as L8080=localhost:80 or
+// D1080 into an ssh option.
+func puttyForward(forward string) (key, value, note string) {
+	spec := strings.TrimLeft(forward, "46")
+	if spec == "" {
+		return "", "", fmt.Sprintf("port forwarding %q not imported", forward)
+	}
+	kind, rest := spec[0], spec[1:]
+	listen, dest, _ := strings.Cut(rest, "=")
+	switch kind {
+	case 'L':
+		return "LocalForward", listen + " " + dest, ""
+	case 'R':
+		return "RemoteForward", listen + " " + dest, ""
+	case 'D':
+		return "DynamicForward", listen, ""
+	}
+	return "", "", fmt.Sprintf("port forwarding %q not imported", forward)
+}
+
+// puttyProxy maps PuTTY's proxy settings. An SSH proxy becomes a jump host;
+// other proxy types cannot be expressed and are noted.
+func puttyProxy(s *models.Server, settings map[string]string) []string {
+	method := settings["ProxyMethod"]
+	if method == "" || method == "0" || settings["ProxyHost"] == "" {
+		return nil
+	}
+	hop := settings["ProxyHost"]
+	if port := settings["ProxyPort"]; port != "" && port != "22" && method == "6" {
+		hop += ":" + port
+	}
+	if user := settings["ProxyUsername"]; user != "" {
+		hop = user + "@" + hop
+	}
+
+	switch method {
+	case "6":
+		s.Jump = models.JumpChain{hop}
+		return nil
+	case "1", "2":
+		return []string{fmt.Sprintf("SOCKS proxy %s:%s not imported; use ProxyCommand \"nc -X 5 -x %s:%s %%h %%p\" if needed",
+			settings["ProxyHost"], settings["ProxyPort"], settings["ProxyHost"], settings["ProxyPort"])}
+	case "3":
+		return []string{fmt.Sprintf("HTTP proxy %s:%s not imported", settings["ProxyHost"], settings["ProxyPort"])}
+	case "5":
+		return []string{fmt.Sprintf("local proxy command %q not imported", settings["ProxyTelnetCommand"])}
+	}
+	return []string{fmt.Sprintf("proxy %s not imported", hop)}
+}
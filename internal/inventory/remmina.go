@@ -0,0 +1,139 @@
+package inventory
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// remminaNoted lists the Remmina settings whose loss is worth mentioning
+// when they are set.
+var remminaNoted = []string{"exec", "password", "ssh_passphrase", "ssh_tunnel_password", "ssh_tunnel_passphrase"}
+
+// ParseRemminaFiles reads Remmina connection files. path is a directory of
+// .remmina files or a single file. Connections that are not SSH are
+// returned as skipped with the reason.
+func ParseRemminaFiles(path string) (hosts []Host, skipped []string, err error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, nil, err
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.remmina")); err != nil {
+			return nil, nil, err
+		}
+		sort.Strings(files)
+	}
+
+	for _, file := range files {
+		settings, err := readRemminaFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", file, err)
+		}
+		name := settings["name"]
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+		if protocol := settings["protocol"]; !strings.EqualFold(protocol, "SSH") {
+			skipped = append(skipped, fmt.Sprintf("%s: protocol %s", name, protocol))
+			continue
+		}
+		if settings["server"] == "" {
+			skipped = append(skipped, fmt.Sprintf("%s: no server", name))
+			continue
+		}
+		hosts = append(hosts, remminaHost(name, settings))
+	}
+	return hosts, skipped, nil
+}
+
+// readRemminaFile returns the keys of the [remmina] section.
+func readRemminaFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	settings := make(map[string]string)
+	inSection := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSection = line == "[remmina]"
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && inSection {
+			settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return settings, scanner.Err()
+}
+
+func remminaHost(name string, settings map[string]string) Host {
+	h := Host{Server: models.Server{Name: name, User: settings["username"]}}
+	s := &h.Server
+	s.Host, s.Port = splitHostPort(settings["server"])
+
+	if key := settings["ssh_privatekey"]; key != "" {
+		s.Key = key
+	}
+	if group := settings["group"]; group != "" {
+		s.Tags = []string{tagName(group)}
+	}
+
+	if settings["ssh_tunnel_enabled"] == "1" && settings["ssh_tunnel_server"] != "" {
+		host, port := splitHostPort(settings["ssh_tunnel_server"])
+		hop := host
+		if port != 0 {
+			hop = net.JoinHostPort(host, strconv.Itoa(port))
+		}
+		if user := settings["ssh_tunnel_username"]; user != "" {
+			hop = user + "@" + hop
+		}
+		s.Jump = models.JumpChain{hop}
+		if key := settings["ssh_tunnel_privatekey"]; key != "" && key != s.Key {
+			h.Notes = append(h.Notes, fmt.Sprintf("jump host key %s not imported; add the jump host as a server with that key", key))
+		}
+	}
+
+	var lost []string
+	for _, key := range remminaNoted {
+		if settings[key] != "" {
+			lost = append(lost, key)
+		}
+	}
+	if len(lost) > 0 {
+		h.Notes = append(h.Notes, "not imported: "+strings.Join(lost, ", "))
+	}
+	return h
+}
+
+// splitHostPort splits host[:port], leaving port 0 when it is absent or 22.
+func splitHostPort(value string) (string, int) {
+	host, portStr, err := net.SplitHostPort(value)
+	if err != nil {
+		return strings.Trim(value, "[]"), 0
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port == 22 {
+		return host, 0
+	}
+	return host, port
+}
+
+// tagName turns a folder or group label into a tag: lower case, with runs
+// of spaces and slashes replaced by a dash.
+func tagName(label string) string {
+	fields := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return r == ' ' || r == '/' || r == '\t'
+	})
+	return strings.Join(fields, "-")
+}
@@ -0,0 +1,148 @@
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestParsePuttySessions(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Default%20Settings": "HostName=\nProtocol=ssh\n",
+		"prod%20web": "HostName=deploy@web.example.com\nPortNumber=2222\nProtocol=ssh\n" +
+			"PublicKeyFile=/home/me/.ssh/web.ppk\nAgentFwd=1\nPortForwardings=L8080=localhost:80,4D1080,X1\n" +
+			"ProxyMethod=6\nProxyHost=bastion.example.com\nProxyPort=2200\nProxyUsername=ops\nRemoteCommand=htop\n",
+		"db": "HostName=db.example.com\nUserName=admin\nPortNumber=22\nProtocol=ssh\nPublicKeyFile=/home/me/.ssh/db\n" +
+			"ProxyMethod=2\nProxyHost=socks.example.com\nProxyPort=1080\n",
+		"router": "HostName=192.168.1.1\nProtocol=telnet\n",
+		"empty":  "HostName=\nProtocol=ssh\n",
+	})
+
+	hosts, skipped, err := ParsePuttySessions(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"empty: no host name", "router: protocol telnet"}) {
+		t.Errorf("Expected empty and router to be skipped, got %v", skipped)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("Expected 2 hosts, got %d", len(hosts))
+	}
+
+	db := hosts[0]
+	if !reflect.DeepEqual(db.Server, models.Server{Name: "db", Host: "db.example.com", User: "admin", Key: "/home/me/.ssh/db"}) {
+		t.Errorf("Unexpected db server %+v", db.Server)
+	}
+	if len(db.Notes) != 1 || !strings.Contains(db.Notes[0], "SOCKS proxy socks.example.com:1080 not imported") {
+		t.Errorf("Expected a note about the SOCKS proxy, got %v", db.Notes)
+	}
+
+	web := hosts[1]
+	expected := models.Server{
+		Name: "prod web", Host: "web.example.com", User: "deploy", Port: 2222,
+		Jump: models.JumpChain{"ops@bastion.example.com:2200"},
+		Options: map[string]interface{}{
+			"ForwardAgent":   "yes",
+			"LocalForward":   []interface{}{"8080 localhost:80"},
+			"DynamicForward": []interface{}{"1080"},
+		},
+	}
+	if !reflect.DeepEqual(web.Server, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, web.Server)
+	}
+	notes := strings.Join(web.Notes, "\n")
+	for _, want := range []string{"web.ppk is in PuTTY format", `port forwarding "X1" not imported`, "not imported: RemoteCommand"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("Expected a note containing %q, got %v", want, web.Notes)
+		}
+	}
+}
+
+func TestParseRemminaFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.remmina": "[remmina]\nname=Prod DB\nprotocol=SSH\nserver=10.0.1.5:2222\nusername=admin\n" +
+			"ssh_privatekey=/home/me/.ssh/db\ngroup=Production/EU\nssh_tunnel_enabled=1\n" +
+			"ssh_tunnel_server=bastion.example.com\nssh_tunnel_username=ops\nssh_tunnel_privatekey=/home/me/.ssh/ops\npassword=c2VjcmV0\n",
+		"b.remmina": "[remmina]\nname=Desktop\nprotocol=RDP\nserver=10.0.2.5\n",
+		"c.remmina": "[remmina]\nprotocol=SSH\nserver=[fe80::1]:22\n",
+		"notes.txt": "not a connection",
+	})
+
+	hosts, skipped, err := ParseRemminaFiles(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"Desktop: protocol RDP"}) {
+		t.Errorf("Expected the RDP connection to be skipped, got %v", skipped)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("Expected 2 hosts, got %d", len(hosts))
+	}
+
+	expected := models.Server{
+		Name: "Prod DB", Host: "10.0.1.5", User: "admin", Port: 2222, Key: "/home/me/.ssh/db",
+		Tags: []string{"production-eu"}, Jump: models.JumpChain{"ops@bastion.example.com"},
+	}
+	if !reflect.DeepEqual(hosts[0].Server, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, hosts[0].Server)
+	}
+	notes := strings.Join(hosts[0].Notes, "\n")
+	if !strings.Contains(notes, "jump host key /home/me/.ssh/ops not imported") || !strings.Contains(notes, "not imported: password") {
+		t.Errorf("Expected notes about the tunnel key and password, got %v", hosts[0].Notes)
+	}
+	if hosts[1].Server.Name != "c" || hosts[1].Server.Host != "fe80::1" || hosts[1].Server.Port != 0 {
+		t.Errorf("Expected c at fe80::1, got %+v", hosts[1].Server)
+	}
+}
+
+func TestParseJSONHosts(t *testing.T) {
+	data := `{"hosts": [
+		{"id": 7, "label": "web", "address": "10.0.0.1", "ssh_config": {"port": 2222, "identity": {"username": "deploy"}},
+		 "group": {"label": "Prod Web"}, "ssh_key": {"label": "laptop"}, "password": "x", "color": "red"},
+		{"name": "db", "host": "10.0.1.1", "user": "admin", "identity_file": "~/.ssh/db", "jump": ["bastion"], "tags": ["db", "prod"]},
+		{"label": "broken", "port": 22}
+	]}`
+
+	hosts, skipped, err := ParseJSONHosts([]byte(data))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"broken: no address"}) {
+		t.Errorf("Expected broken to be skipped, got %v", skipped)
+	}
+	expected := []models.Server{
+		{Name: "web", Host: "10.0.0.1", User: "deploy", Port: 2222, Tags: []string{"prod-web"}},
+		{Name: "db", Host: "10.0.1.1", User: "admin", Key: "~/.ssh/db", Jump: models.JumpChain{"bastion"}, Tags: []string{"db", "prod"}},
+	}
+	for i, want := range expected {
+		if !reflect.DeepEqual(hosts[i].Server, want) {
+			t.Errorf("Expected %+v, got %+v", want, hosts[i].Server)
+		}
+	}
+	notes := strings.Join(hosts[0].Notes, "\n")
+	if !strings.Contains(notes, "key stored in the app's vault") || !strings.Contains(notes, "not imported: password") {
+		t.Errorf("Expected notes about the vault key and password, got %v", hosts[0].Notes)
+	}
+	if len(hosts[1].Notes) != 0 {
+		t.Errorf("Expected no notes for db, got %v", hosts[1].Notes)
+	}
+
+	if _, _, err := ParseJSONHosts([]byte(`{"servers": []}`)); err == nil {
+		t.Errorf("Expected an error without a hosts list")
+	}
+}
@@ -0,0 +1,306 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TerraformInstance is one instance of a managed resource in a state file.
+type TerraformInstance struct {
+	// Address is the resource address, such as module.app.aws_instance.web[0].
+	Address string
+	// Name is the resource name with the index, such as web-0.
+	Name       string
+	Type       string
+	Attributes map[string]interface{}
+}
+
+// terraformType holds the built-in defaults of a supported resource type.
+type terraformType struct {
+	name    string
+	public  string
+	private string
+	user    string
+}
+
+// terraformTypes are the resource types that describe SSH-reachable
+// instances. Attribute references may list fallbacks separated by "|".
+var terraformTypes = map[string]terraformType{
+	"aws_instance": {
+		name:    "tags.Name",
+		public:  "public_ip|public_dns",
+		private: "private_ip|private_dns",
+	},
+	"google_compute_instance": {
+		name:    "name",
+		public:  "network_interface.0.access_config.0.nat_ip",
+		private: "network_interface.0.network_ip",
+	},
+	"azurerm_linux_virtual_machine": {
+		name:    "name",
+		public:  "public_ip_address",
+		private: "private_ip_address",
+		user:    "admin_username",
+	},
+	"hcloud_server": {
+		name:    "name",
+		public:  "ipv4_address|ipv6_address",
+		private: "network.0.ip",
+	},
+	"digitalocean_droplet": {
+		name:    "name",
+		public:  "ipv4_address|ipv6_address",
+		private: "ipv4_address_private",
+	},
+}
+
+// TerraformTypes returns the supported resource types, sorted.
+func TerraformTypes() []string {
+	return sortedKeys(terraformTypes)
+}
+
+// TerraformMapping chooses how instances become servers. Rules for a
+// resource type override the defaults field by field.
+type TerraformMapping struct {
+	Defaults TerraformRule            `yaml:"defaults"`
+	Types    map[string]TerraformRule `yaml:"types"`
+}
+
+// TerraformRule maps instance attributes to server fields. Values are
+// templates where {path} is replaced by an attribute, addressed with dots
+// (tags.Role, network_interface.0.network_ip) and with "|" between
+// fallbacks. Host may also be "public" or "private" to use the address the
+// resource type provides, and Tags entries that come out empty are dropped.
+type TerraformRule struct {
+	Name string   `yaml:"name"`
+	Host string   `yaml:"host"`
+	User string   `yaml:"user"`
+	Port string   `yaml:"port"`
+	Key  string   `yaml:"key"`
+	Tags []string `yaml:"tags"`
+}
+
+// LoadTerraformMapping reads a mapping file.
+func LoadTerraformMapping(path string) (TerraformMapping, error) {
+	var mapping TerraformMapping
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mapping, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&mapping); err != nil && !errors.Is(err, io.EOF) {
+		return mapping, fmt.Errorf("%s: %w", path, err)
+	}
+	for t := range mapping.Types {
+		if _, ok := terraformTypes[t]; !ok {
+			return mapping, fmt.Errorf("%s: unsupported resource type %q", path, t)
+		}
+	}
+	return mapping, nil
+}
+
+func (m TerraformMapping) rule(resourceType string) TerraformRule {
+	rule := m.Defaults
+	override := m.Types[resourceType]
+	if override.Name != "" {
+		rule.Name = override.Name
+	}
+	if override.Host != "" {
+		rule.Host = override.Host
+	}
+	if override.User != "" {
+		rule.User = override.User
+	}
+	if override.Port != "" {
+		rule.Port = override.Port
+	}
+	if override.Key != "" {
+		rule.Key = override.Key
+	}
+	if override.Tags != nil {
+		rule.Tags = override.Tags
+	}
+	return rule
+}
+
+type terraformState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   interface{}            `json:"index_key"`
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// ParseTerraformState returns the instances of the supported resource types
+// in a version 4 state file, in file order.
+func ParseTerraformState(data []byte) ([]TerraformInstance, error) {
+	var state terraformState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported state version %d, expected 4", state.Version)
+	}
+
+	var instances []TerraformInstance
+	for _, r := range state.Resources {
+		if _, ok := terraformTypes[r.Type]; !ok || r.Mode != "managed" {
+			continue
+		}
+		address := r.Type + "." + r.Name
+		if r.Module != "" {
+			address = r.Module + "." + address
+		}
+		for _, inst := range r.Instances {
+			a, name := address, r.Name
+			switch key := inst.IndexKey.(type) {
+			case float64:
+				index := strconv.FormatFloat(key, 'f', -1, 64)
+				a, name = a+"["+index+"]", name+"-"+index
+			case string:
+				a, name = a+"["+strconv.Quote(key)+"]", name+"-"+key
+			}
+			instances = append(instances, TerraformInstance{Address: a, Name: name, Type: r.Type, Attributes: inst.Attributes})
+		}
+	}
+	return instances, nil
+}
+
+// TerraformHosts maps instances to servers. Instances without an address
+// are returned as skipped with the reason.
+func TerraformHosts(instances []TerraformInstance, mapping TerraformMapping) (hosts []Host, skipped []string) {
+	for _, inst := range instances {
+		defaults := terraformTypes[inst.Type]
+		rule := mapping.rule(inst.Type)
+
+		name := expandTemplate(rule.Name, inst.Attributes)
+		if rule.Name == "" {
+			name = lookupAttribute(inst.Attributes, defaults.name)
+		}
+		if name == "" {
+			name = inst.Name
+		}
+
+		var host string
+		switch rule.Host {
+		case "", "public":
+			host = lookupAttribute(inst.Attributes, defaults.public)
+			if host == "" && rule.Host == "" {
+				host = lookupAttribute(inst.Attributes, defaults.private)
+			}
+		case "private":
+			host = lookupAttribute(inst.Attributes, defaults.private)
+		default:
+			host = expandTemplate(rule.Host, inst.Attributes)
+		}
+		if host == "" {
+			skipped = append(skipped, fmt.Sprintf("%s: no address", inst.Address))
+			continue
+		}
+
+		h := Host{Server: models.Server{Name: name, Host: host}}
+		h.Server.User = expandTemplate(rule.User, inst.Attributes)
+		if rule.User == "" && defaults.user != "" {
+			h.Server.User = lookupAttribute(inst.Attributes, defaults.user)
+		}
+		h.Server.Key = expandTemplate(rule.Key, inst.Attributes)
+		if port := expandTemplate(rule.Port, inst.Attributes); port != "" {
+			n, err := strconv.Atoi(port)
+			if err != nil || n < 1 || n > 65535 {
+				h.Notes = append(h.Notes, fmt.Sprintf("invalid port %q ignored", port))
+			} else {
+				h.Server.Port = n
+			}
+		}
+		for _, tag := range rule.Tags {
+			if tag = expandTemplate(tag, inst.Attributes); tag != "" {
+				h.Server.Tags = append(h.Server.Tags, tag)
+			}
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, skipped
+}
+
+// expandTemplate replaces every {path} in template by the attribute value.
+// A template whose references all come out empty expands to "".
+func expandTemplate(template string, attributes map[string]interface{}) string {
+	var b strings.Builder
+	found, empty := false, true
+	for {
+		open := strings.Index(template, "{")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(template[open:], "}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(template[:open])
+		value := lookupAttribute(attributes, template[open+1:open+end])
+		if value != "" {
+			empty = false
+		}
+		b.WriteString(value)
+		found = true
+		template = template[open+end+1:]
+	}
+	b.WriteString(template)
+	if found && empty {
+		return ""
+	}
+	return b.String()
+}
+
+// lookupAttribute resolves a dotted path, trying "|"-separated fallbacks in
+// order until one is set.
+func lookupAttribute(attributes map[string]interface{}, paths string) string {
+	if paths == "" {
+		return ""
+	}
+	for _, path := range strings.Split(paths, "|") {
+		var value interface{} = attributes
+		for _, part := range strings.Split(strings.TrimSpace(path), ".") {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				value = v[part]
+			case []interface{}:
+				i, err := strconv.Atoi(part)
+				if err != nil || i < 0 || i >= len(v) {
+					value = nil
+				} else {
+					value = v[i]
+				}
+			default:
+				value = nil
+			}
+		}
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+const stateFixture = `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed", "type": "aws_instance", "name": "web",
+      "instances": [
+        {"index_key": 0, "attributes": {"public_ip": "203.0.113.10", "private_ip": "10.0.0.10", "tags": {"Name": "web-a", "Role": "web"}}},
+        {"index_key": 1, "attributes": {"public_ip": "", "private_ip": "10.0.0.11", "tags": {"Role": "web"}}}
+      ]
+    },
+    {
+      "module": "module.db", "mode": "managed", "type": "google_compute_instance", "name": "primary",
+      "instances": [
+        {"attributes": {"name": "db-1", "network_interface": [{"network_ip": "10.1.0.5", "access_config": []}], "labels": {"env": "prod"}}}
+      ]
+    },
+    {
+      "mode": "managed", "type": "azurerm_linux_virtual_machine", "name": "vm",
+      "instances": [
+        {"attributes": {"name": "vm-1", "public_ip_address": "198.51.100.7", "admin_username": "azureuser"}}
+      ]
+    },
+    {
+      "mode": "managed", "type": "hcloud_server", "name": "nodes",
+      "instances": [
+        {"index_key": "a", "attributes": {"name": "", "ipv4_address": "", "network": []}}
+      ]
+    },
+    {
+      "mode": "data", "type": "aws_instance", "name": "lookup",
+      "instances": [{"attributes": {"public_ip": "192.0.2.1"}}]
+    },
+    {
+      "mode": "managed", "type": "aws_security_group", "name": "sg",
+      "instances": [{"attributes": {"name": "sg"}}]
+    }
+  ]
+}`
+
+func TestParseTerraformState(t *testing.T) {
+	instances, err := ParseTerraformState([]byte(stateFixture))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	addresses := make([]string, len(instances))
+	names := make([]string, len(instances))
+	for i, inst := range instances {
+		addresses[i], names[i] = inst.Address, inst.Name
+	}
+	expected := []string{
+		"aws_instance.web[0]",
+		"aws_instance.web[1]",
+		"module.db.google_compute_instance.primary",
+		"azurerm_linux_virtual_machine.vm",
+		`hcloud_server.nodes["a"]`,
+	}
+	if !reflect.DeepEqual(addresses, expected) {
+		t.Errorf("Expected %v, got %v", expected, addresses)
+	}
+	if !reflect.DeepEqual(names, []string{"web-0", "web-1", "primary", "vm", "nodes-a"}) {
+		t.Errorf("Expected instance names, got %v", names)
+	}
+
+	if _, err := ParseTerraformState([]byte(`{"version": 3}`)); err == nil {
+		t.Errorf("Expected an error for state version 3")
+	}
+}
+
+func TestTerraformHosts(t *testing.T) {
+	instances, err := ParseTerraformState([]byte(stateFixture))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		hosts, skipped := TerraformHosts(instances, TerraformMapping{})
+		expected := []models.Server{
+			{Name: "web-a", Host: "203.0.113.10"},
+			{Name: "web-1", Host: "10.0.0.11"},
+			{Name: "db-1", Host: "10.1.0.5"},
+			{Name: "vm-1", Host: "198.51.100.7", User: "azureuser"},
+		}
+		if len(hosts) != len(expected) {
+			t.Fatalf("Expected %d hosts, got %d", len(expected), len(hosts))
+		}
+		for i, want := range expected {
+			if !reflect.DeepEqual(hosts[i].Server, want) {
+				t.Errorf("Expected %+v, got %+v", want, hosts[i].Server)
+			}
+		}
+		if !reflect.DeepEqual(skipped, []string{`hcloud_server.nodes["a"]: no address`}) {
+			t.Errorf("Expected the hcloud server to be skipped, got %v", skipped)
+		}
+	})
+
+	t.Run("mapping", func(t *testing.T) {
+		mapping := TerraformMapping{
+			Defaults: TerraformRule{Host: "private", User: "ubuntu", Tags: []string{"terraform", "{tags.Role}"}},
+			Types: map[string]TerraformRule{
+				"google_compute_instance": {Name: "{labels.hostname|name}.internal", Port: "2222", Tags: []string{"{labels.env}"}},
+			},
+		}
+		hosts, _ := TerraformHosts(instances, mapping)
+		expected := []models.Server{
+			{Name: "web-a", Host: "10.0.0.10", User: "ubuntu", Tags: []string{"terraform", "web"}},
+			{Name: "web-1", Host: "10.0.0.11", User: "ubuntu", Tags: []string{"terraform", "web"}},
+			{Name: "db-1.internal", Host: "10.1.0.5", User: "ubuntu", Port: 2222, Tags: []string{"prod"}},
+		}
+		if len(hosts) != len(expected) {
+			t.Fatalf("Expected %d hosts, got %d", len(expected), len(hosts))
+		}
+		for i, want := range expected {
+			if !reflect.DeepEqual(hosts[i].Server, want) {
+				t.Errorf("Expected %+v, got %+v", want, hosts[i].Server)
+			}
+		}
+	})
+}
+
+func TestLoadTerraformMapping(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	os.WriteFile(valid, []byte("defaults:\n  host: private\ntypes:\n  aws_instance:\n    user: ec2-user\n"), 0644)
+	mapping, err := LoadTerraformMapping(valid)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rule := mapping.rule("aws_instance"); rule.Host != "private" || rule.User != "ec2-user" {
+		t.Errorf("Expected the type rule to extend the defaults, got %+v", rule)
+	}
+
+	for name, content := range map[string]string{
+		"unknown type":  "types:\n  aws_lambda_function:\n    name: x\n",
+		"unknown field": "defaults:\n  hostname: x\n",
+	} {
+		path := filepath.Join(dir, "invalid.yaml")
+		os.WriteFile(path, []byte(content), 0644)
+		if _, err := LoadTerraformMapping(path); err == nil {
+			t.Errorf("%s: Expected an error", name)
+		}
+	}
+}
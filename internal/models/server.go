@@ -1,5 +1,12 @@
 package models
 
+import (
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
 type Server struct {
 	Name    string                 `yaml:"name" json:"name"`
 	Host    string                 `yaml:"host" json:"host"`
@@ -7,9 +14,71 @@ type Server struct {
 	Port    int                    `yaml:"port,omitempty" json:"port,omitempty"`
 	Tags    []string               `yaml:"tags,omitempty" json:"tags,omitempty"`
 	Key     string                 `yaml:"key,omitempty" json:"key,omitempty"`
+	Jump    JumpChain              `yaml:"jump,omitempty" json:"jump,omitempty"`
 	Options map[string]interface{} `yaml:"options,omitempty" json:"options,omitempty"`
 }
 
+// JumpHops returns the jump chain of the server. A ProxyJump entry in
+// Options is honoured when no explicit jump chain is configured.
+func (s Server) JumpHops() []string {
+	if len(s.Jump) > 0 {
+		return s.Jump
+	}
+	for key, value := range s.Options {
+		if !strings.EqualFold(key, "ProxyJump") {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok || strings.EqualFold(strings.TrimSpace(str), "none") {
+			return nil
+		}
+		return SplitJumpChain(str)
+	}
+	return nil
+}
+
+// JumpChain lists the hops used to reach a server, either as sshy server
+// names or as raw [user@]host[:port] addresses. Besides a list, a single
+// comma-separated string is accepted, mirroring ssh's ProxyJump syntax.
+type JumpChain []string
+
+func (j *JumpChain) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*j = SplitJumpChain(value.Value)
+		return nil
+	}
+	var hops []string
+	if err := value.Decode(&hops); err != nil {
+		return err
+	}
+	*j = hops
+	return nil
+}
+
+func (j *JumpChain) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*j = SplitJumpChain(str)
+		return nil
+	}
+	var hops []string
+	if err := json.Unmarshal(data, &hops); err != nil {
+		return err
+	}
+	*j = hops
+	return nil
+}
+
+func SplitJumpChain(value string) JumpChain {
+	var hops JumpChain
+	for _, hop := range strings.Split(value, ",") {
+		if hop = strings.TrimSpace(hop); hop != "" {
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
 type Servers []Server
 
 type ServerSource int
@@ -23,4 +92,8 @@ const (
 type ServerWithSource struct {
 	Server Server
 	Source ServerSource
+	// Origin names the additional shared source a shared or overridden
+	// server comes from. It is empty for the main servers file and for
+	// private servers.
+	Origin string
 }
@@ -1,6 +1,12 @@
 package models
 
-import "testing"
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
 
 func TestServerStruct(t *testing.T) {
 	server := Server{
@@ -93,3 +99,71 @@ func TestServerDefaultValues(t *testing.T) {
 		t.Errorf("Expected nil options, got %v", server.Options)
 	}
 }
+
+func TestJumpChainUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		yamlData string
+		jsonData string
+		expected []string
+	}{
+		{
+			name:     "list",
+			yamlData: "jump: [bastion, ops@inner:2222]",
+			jsonData: `{"jump": ["bastion", "ops@inner:2222"]}`,
+			expected: []string{"bastion", "ops@inner:2222"},
+		},
+		{
+			name:     "comma separated string",
+			yamlData: "jump: bastion, ops@inner:2222",
+			jsonData: `{"jump": "bastion, ops@inner:2222"}`,
+			expected: []string{"bastion", "ops@inner:2222"},
+		},
+		{
+			name:     "single hop",
+			yamlData: "jump: bastion",
+			jsonData: `{"jump": "bastion"}`,
+			expected: []string{"bastion"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromYAML, fromJSON Server
+			if err := yaml.Unmarshal([]byte(tt.yamlData), &fromYAML); err != nil {
+				t.Fatalf("yaml.Unmarshal() error = %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.jsonData), &fromJSON); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			for _, got := range [][]string{fromYAML.Jump, fromJSON.Jump} {
+				if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+					t.Errorf("Expected jump chain %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestJumpHops(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   Server
+		expected []string
+	}{
+		{"no jump", Server{}, nil},
+		{"jump field", Server{Jump: JumpChain{"bastion"}}, []string{"bastion"}},
+		{"ProxyJump option", Server{Options: map[string]interface{}{"ProxyJump": "a,b"}}, []string{"a", "b"}},
+		{"ProxyJump none", Server{Options: map[string]interface{}{"proxyjump": "none"}}, nil},
+		{"jump field wins", Server{Jump: JumpChain{"a"}, Options: map[string]interface{}{"ProxyJump": "b"}}, []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.server.JumpHops()
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("JumpHops() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package probe
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPreBannerLines bounds the lines a server may send before its
+// identification string (RFC 4253 section 4.2).
+const maxPreBannerLines = 20
+
+// Result is the outcome of a single reachability probe.
+type Result struct {
+	Address string
+	Latency time.Duration
+	Banner  string
+	Err     error
+}
+
+func (r Result) Up() bool {
+	return r.Err == nil
+}
+
+// Address returns host:port for a server, defaulting to port 22.
+func Address(host string, port int) string {
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// Dial connects to address and reads the SSH identification banner. The
+// latency covers the TCP connect and the banner.
+func Dial(ctx context.Context, address string, timeout time.Duration) Result {
+	result := Result{Address: address}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		result.Err = describe(err)
+		return result
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetReadDeadline(deadline)
+	}
+
+	result.Banner, result.Err = ReadBanner(conn)
+	result.Err = describe(result.Err)
+	result.Latency = time.Since(start)
+	return result
+}
+
+// ReadBanner reads lines from r until the SSH identification string and
+// returns it without the line ending.
+func ReadBanner(r io.Reader) (string, error) {
+	reader := bufio.NewReaderSize(r, 256)
+	for i := 0; i < maxPreBannerLines; i++ {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			return line, nil
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", fmt.Errorf("connection closed before SSH banner")
+			}
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no SSH banner in the first %d lines", maxPreBannerLines)
+}
+
+// Version extracts the software version from a banner such as
+// "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3ubuntu13".
+func Version(banner string) string {
+	parts := strings.SplitN(banner, "-", 3)
+	if len(parts) < 3 {
+		return banner
+	}
+	return parts[2]
+}
+
+// describe shortens the most common dial errors to what a user needs to see.
+func describe(err error) error {
+	if err == nil {
+		return nil
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("timed out")
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return fmt.Errorf("cannot resolve %s", dnsErr.Name)
+	}
+	if strings.Contains(err.Error(), "connection refused") {
+		return fmt.Errorf("connection refused")
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listen(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestDial(t *testing.T) {
+	ssh := listen(t, func(c net.Conn) { c.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n")) })
+	preamble := listen(t, func(c net.Conn) { c.Write([]byte("welcome\r\nSSH-2.0-dropbear_2022.83\r\n")) })
+	silent := listen(t, func(c net.Conn) { time.Sleep(time.Second) })
+	closing := listen(t, func(c net.Conn) {})
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	refused := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		address string
+		banner  string
+		wantErr string
+	}{
+		{"ssh server", ssh, "SSH-2.0-OpenSSH_9.6", ""},
+		{"lines before banner", preamble, "SSH-2.0-dropbear_2022.83", ""},
+		{"no banner", silent, "", "timed out"},
+		{"closed without banner", closing, "", "connection closed before SSH banner"},
+		{"refused", refused, "", "connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Dial(context.Background(), tt.address, 200*time.Millisecond)
+			if tt.wantErr != "" {
+				if result.Up() || !strings.Contains(result.Err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, result.Err)
+				}
+				return
+			}
+			if !result.Up() {
+				t.Fatalf("Unexpected error: %v", result.Err)
+			}
+			if result.Banner != tt.banner {
+				t.Errorf("Expected banner %q, got %q", tt.banner, result.Banner)
+			}
+			if result.Latency <= 0 {
+				t.Errorf("Expected positive latency, got %v", result.Latency)
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		banner   string
+		expected string
+	}{
+		{"SSH-2.0-OpenSSH_9.6p1 Ubuntu-3ubuntu13", "OpenSSH_9.6p1 Ubuntu-3ubuntu13"},
+		{"SSH-2.0-dropbear_2022.83", "dropbear_2022.83"},
+		{"garbage", "garbage"},
+	}
+
+	for _, tt := range tests {
+		if got := Version(tt.banner); got != tt.expected {
+			t.Errorf("Version(%q) = %q, expected %q", tt.banner, got, tt.expected)
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		host     string
+		port     int
+		expected string
+	}{
+		{"example.com", 0, "example.com:22"},
+		{"example.com", 2222, "example.com:2222"},
+		{"::1", 22, "[::1]:22"},
+	}
+
+	for _, tt := range tests {
+		if got := Address(tt.host, tt.port); got != tt.expected {
+			t.Errorf("Address(%q, %d) = %q, expected %q", tt.host, tt.port, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxScanAddresses bounds the addresses a single scan may cover.
+const MaxScanAddresses = 65536
+
+// ExpandNetwork returns the addresses of a CIDR network in order, or the
+// single address given. For IPv4 networks larger than /31 the network and
+// broadcast addresses are left out.
+func ExpandNetwork(s string) ([]netip.Addr, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		return []netip.Addr{addr}, nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network %q", s)
+	}
+	prefix = prefix.Masked()
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	if hostBits > 16 {
+		return nil, fmt.Errorf("network %s has more than %d addresses", prefix, MaxScanAddresses)
+	}
+
+	var addrs []netip.Addr
+	for addr := prefix.Addr(); prefix.Contains(addr); addr = addr.Next() {
+		addrs = append(addrs, addr)
+		if !addr.Next().IsValid() {
+			break
+		}
+	}
+	if prefix.Addr().Is4() && hostBits > 1 {
+		addrs = addrs[1 : len(addrs)-1]
+	}
+	return addrs, nil
+}
+
+// Limiter bounds how many tasks run at once and how many start per second,
+// so that the probes of a scan and the lookups that follow share one budget.
+type Limiter struct {
+	sem    chan struct{}
+	ticker *time.Ticker
+	first  bool
+}
+
+// NewLimiter runs at most concurrency tasks at once, starting at most rate
+// per second; a rate of 0 means no limit. Stop releases its ticker.
+func NewLimiter(concurrency, rate int) *Limiter {
+	l := &Limiter{sem: make(chan struct{}, max(concurrency, 1)), first: true}
+	if rate > 0 {
+		// Rates above one per nanosecond would make the interval zero.
+		l.ticker = time.NewTicker(max(time.Second/time.Duration(rate), time.Nanosecond))
+	}
+	return l
+}
+
+// Acquire waits until a task may start and reports false if ctx is done
+// first. It must be called from one goroutine; Release frees the slot.
+func (l *Limiter) Acquire(ctx context.Context) bool {
+	// The first task starts at once, the others wait their turn.
+	if l.ticker != nil && !l.first {
+		select {
+		case <-l.ticker.C:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	l.first = false
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (l *Limiter) Release() {
+	<-l.sem
+}
+
+func (l *Limiter) Stop() {
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+}
+
+// ScanOptions control what a scan probes and how fast.
+type ScanOptions struct {
+	Ports   []int
+	Limiter *Limiter
+	Timeout time.Duration
+}
+
+// Hit is an address and port where an SSH server answered.
+type Hit struct {
+	Result
+	Addr netip.Addr
+	Port int
+}
+
+// Scan probes every port of every address and returns the ones where an SSH
+// server answered, in address then port order. Closed ports and services
+// that send no SSH banner are left out.
+func Scan(ctx context.Context, addrs []netip.Addr, opts ScanOptions) []Hit {
+	var (
+		mu   sync.Mutex
+		hits []Hit
+		wg   sync.WaitGroup
+	)
+scan:
+	for _, addr := range addrs {
+		for _, port := range opts.Ports {
+			if !opts.Limiter.Acquire(ctx) {
+				break scan
+			}
+			wg.Add(1)
+			go func(addr netip.Addr, port int) {
+				defer wg.Done()
+				defer opts.Limiter.Release()
+				result := Dial(ctx, netip.AddrPortFrom(addr, uint16(port)).String(), opts.Timeout)
+				if !result.Up() {
+					return
+				}
+				mu.Lock()
+				hits = append(hits, Hit{Result: result, Addr: addr, Port: port})
+				mu.Unlock()
+			}(addr, port)
+		}
+	}
+	wg.Wait()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if c := hits[i].Addr.Compare(hits[j].Addr); c != 0 {
+			return c < 0
+		}
+		return hits[i].Port < hits[j].Port
+	})
+	return hits
+}
@@ -0,0 +1,101 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestExpandNetwork(t *testing.T) {
+	tests := []struct {
+		network string
+		first   string
+		last    string
+		count   int
+		wantErr bool
+	}{
+		{network: "10.0.4.0/24", first: "10.0.4.1", last: "10.0.4.254", count: 254},
+		{network: "10.0.4.77/30", first: "10.0.4.77", last: "10.0.4.78", count: 2},
+		{network: "10.0.4.8/31", first: "10.0.4.8", last: "10.0.4.9", count: 2},
+		{network: "127.0.0.1/32", first: "127.0.0.1", last: "127.0.0.1", count: 1},
+		{network: "10.0.4.5", first: "10.0.4.5", last: "10.0.4.5", count: 1},
+		{network: "fd00::/126", first: "fd00::", last: "fd00::3", count: 4},
+		{network: "10.0.0.0/8", wantErr: true},
+		{network: "10.0.4.0/33", wantErr: true},
+		{network: "example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.network, func(t *testing.T) {
+			addrs, err := ExpandNetwork(tt.network)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %d addresses", len(addrs))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(addrs) != tt.count || addrs[0].String() != tt.first || addrs[len(addrs)-1].String() != tt.last {
+				t.Errorf("Expected %d addresses from %s to %s, got %d from %s to %s",
+					tt.count, tt.first, tt.last, len(addrs), addrs[0], addrs[len(addrs)-1])
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	ssh := listen(t, func(c net.Conn) { c.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n")) })
+	web := listen(t, func(c net.Conn) { c.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n")) })
+	port := func(address string) int {
+		_, p, _ := net.SplitHostPort(address)
+		n, _ := strconv.Atoi(p)
+		return n
+	}
+
+	addrs, _ := ExpandNetwork("127.0.0.0/29")
+	start := time.Now()
+	limiter := NewLimiter(4, 200)
+	defer limiter.Stop()
+	hits := Scan(context.Background(), addrs, ScanOptions{
+		Ports:   []int{port(web), port(ssh)},
+		Limiter: limiter,
+		Timeout: time.Second,
+	})
+	elapsed := time.Since(start)
+
+	if len(hits) != 1 {
+		t.Fatalf("Expected 1 hit, got %+v", hits)
+	}
+	if hits[0].Addr != netip.MustParseAddr("127.0.0.1") || hits[0].Port != port(ssh) || hits[0].Banner != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("Expected the SSH listener, got %+v", hits[0])
+	}
+	// 12 probes at 200 per second take at least 11 intervals of 5ms.
+	if elapsed < 55*time.Millisecond {
+		t.Errorf("Expected the rate limit to slow the scan down, took %s", elapsed)
+	}
+}
+
+func TestScan_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	addrs, _ := ExpandNetwork("127.0.0.0/24")
+	limiter := NewLimiter(1, 1)
+	defer limiter.Stop()
+	hits := Scan(ctx, addrs, ScanOptions{Ports: []int{22}, Limiter: limiter, Timeout: time.Second})
+	if len(hits) != 0 {
+		t.Errorf("Expected no hits after cancelling, got %+v", hits)
+	}
+}
+
+func TestScan_HugeRate(t *testing.T) {
+	addrs, _ := ExpandNetwork("127.0.0.0/30")
+	// Used to panic with a non-positive ticker interval.
+	limiter := NewLimiter(2, 2000000000)
+	defer limiter.Stop()
+	Scan(context.Background(), addrs, ScanOptions{Ports: []int{1}, Limiter: limiter, Timeout: 100 * time.Millisecond})
+}
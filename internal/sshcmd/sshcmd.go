@@ -0,0 +1,225 @@
+package sshcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/config"
+	"github.com/omisai-tech/sshy/internal/models"
+	"github.com/omisai-tech/sshy/internal/sshconfig"
+)
+
+// Invocation is a fully resolved command line for one of the OpenSSH tools.
+type Invocation struct {
+	Program string
+	Args    []string
+}
+
+func (i Invocation) Argv() []string {
+	return append([]string{i.Program}, i.Args...)
+}
+
+// String renders the invocation as a shell-quoted command line.
+func (i Invocation) String() string {
+	return Join(i.Argv())
+}
+
+// Target is a server together with the resolved hops needed to reach it.
+type Target struct {
+	Server models.Server
+	Hops   []models.Server
+}
+
+func NewTarget(server models.Server, inventory models.Servers) (Target, error) {
+	hops, err := config.ResolveJumpChain(server, inventory)
+	if err != nil {
+		return Target{}, err
+	}
+	return Target{Server: server, Hops: hops}, nil
+}
+
+// SSH builds an ssh invocation. A -l flag among the passthrough flags
+// overrides the server's user.
+func SSH(t Target, flags []string, remoteCommand string) (Invocation, error) {
+	args, err := connectionArgs(t, "-p")
+	if err != nil {
+		return Invocation{}, err
+	}
+	args = append(args, StripLoginFlag(flags)...)
+	args = append(args, UserHost(t.Server, LoginUser(t.Server, flags)))
+	if remoteCommand != "" {
+		args = append(args, remoteCommand)
+	}
+	return Invocation{Program: "ssh", Args: args}, nil
+}
+
+// StdioForward builds "ssh -W address" through t's server, which acts as a
+// jump host: like the hops of JumpArgs, it gets only its connection options.
+func StdioForward(t Target, flags []string, address string) (Invocation, error) {
+	args, err := hopArgs(t.Server)
+	if err != nil {
+		return Invocation{}, fmt.Errorf("jump host %s: %w", t.Server.Name, err)
+	}
+	jumpArgs, err := JumpArgs(t.Hops)
+	if err != nil {
+		return Invocation{}, err
+	}
+	args = append(args, jumpArgs...)
+	args = append(args, flags...)
+	args = append(args, "-W", address, UserHost(t.Server, t.Server.User))
+	return Invocation{Program: "ssh", Args: args}, nil
+}
+
+func SFTP(t Target, flags []string) (Invocation, error) {
+	args, err := connectionArgs(t, "-P")
+	if err != nil {
+		return Invocation{}, err
+	}
+	args = append(args, StripLoginFlag(flags)...)
+	args = append(args, UserHost(t.Server, LoginUser(t.Server, flags)))
+	return Invocation{Program: "sftp", Args: args}, nil
+}
+
+// SCP builds an scp invocation. Source and destination are passed through
+// as given; use RemotePath to address the target server.
+func SCP(t Target, flags []string, source, destination string) (Invocation, error) {
+	args, err := connectionArgs(t, "-P")
+	if err != nil {
+		return Invocation{}, err
+	}
+	args = append(args, StripLoginFlag(flags)...)
+	args = append(args, source, destination)
+	return Invocation{Program: "scp", Args: args}, nil
+}
+
+// Rsync builds an rsync invocation whose remote shell is the ssh command
+// sshy would use for the target.
+func Rsync(t Target, flags []string, source, destination string) (Invocation, error) {
+	sshArgs, err := connectionArgs(t, "-p")
+	if err != nil {
+		return Invocation{}, err
+	}
+	args := []string{"-e", Join(append([]string{"ssh"}, sshArgs...))}
+	args = append(args, StripLoginFlag(flags)...)
+	args = append(args, source, destination)
+	return Invocation{Program: "rsync", Args: args}, nil
+}
+
+// RemotePath addresses path on server in scp and rsync syntax.
+func RemotePath(s models.Server, flags []string, path string) string {
+	return UserHost(s, LoginUser(s, flags)) + ":" + path
+}
+
+func UserHost(s models.Server, user string) string {
+	if user == "" {
+		return s.Host
+	}
+	return user + "@" + s.Host
+}
+
+// LoginUser returns the user given with -l in flags, or the server's user.
+func LoginUser(s models.Server, flags []string) string {
+	for i, arg := range flags {
+		if arg == "-l" && i+1 < len(flags) {
+			return flags[i+1]
+		}
+	}
+	return s.User
+}
+
+func StripLoginFlag(flags []string) []string {
+	filtered := []string{}
+	for i := 0; i < len(flags); i++ {
+		if flags[i] == "-l" && i+1 < len(flags) {
+			i++
+			continue
+		}
+		filtered = append(filtered, flags[i])
+	}
+	return filtered
+}
+
+func connectionArgs(t Target, portFlag string) ([]string, error) {
+	args, err := serverArgs(t.Server, portFlag)
+	if err != nil {
+		return nil, fmt.Errorf("server %s: %w", t.Server.Name, err)
+	}
+	jumpArgs, err := JumpArgs(t.Hops)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, jumpArgs...), nil
+}
+
+func serverArgs(s models.Server, portFlag string) ([]string, error) {
+	optionArgs, err := sshconfig.OptionArgs(s.Options)
+	if err != nil {
+		return nil, err
+	}
+	return append(keyPortArgs(s, portFlag), optionArgs...), nil
+}
+
+// hopArgs is serverArgs for a jump host, without the options that only apply
+// to a session, such as forwardings or RequestTTY.
+func hopArgs(s models.Server) ([]string, error) {
+	optionArgs, err := sshconfig.HopOptionArgs(s.Options)
+	if err != nil {
+		return nil, err
+	}
+	return append(keyPortArgs(s, "-p"), optionArgs...), nil
+}
+
+func keyPortArgs(s models.Server, portFlag string) []string {
+	args := []string{}
+	if s.Key != "" {
+		args = append(args, "-i", s.Key)
+	}
+	if s.Port != 0 && s.Port != 22 {
+		args = append(args, portFlag, fmt.Sprintf("%d", s.Port))
+	}
+	return args
+}
+
+// JumpArgs chains the hops through nested ProxyCommands rather than -J, so
+// that every hop is reached with its own key, port and connection options.
+func JumpArgs(hops []models.Server) ([]string, error) {
+	proxyCommand := ""
+	for _, hop := range hops {
+		args, err := hopArgs(hop)
+		if err != nil {
+			return nil, fmt.Errorf("jump host %s: %w", hop.Name, err)
+		}
+		parts := append([]string{"ssh"}, args...)
+		if proxyCommand != "" {
+			// The outer ssh expands %-tokens once, so escape them for the inner one.
+			parts = append(parts, "-o", "ProxyCommand="+strings.ReplaceAll(proxyCommand, "%", "%%"))
+		}
+		parts = append(parts, "-W", "%h:%p", UserHost(hop, hop.User))
+		proxyCommand = Join(parts)
+	}
+	if proxyCommand == "" {
+		return nil, nil
+	}
+	return []string{"-o", "ProxyCommand=" + proxyCommand}, nil
+}
+
+// Join shell-quotes args into a single command line.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r)) {
+			return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		}
+	}
+	return s
+}
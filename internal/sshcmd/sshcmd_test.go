@@ -0,0 +1,495 @@
+package sshcmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestSSH(t *testing.T) {
+	tests := []struct {
+		name          string
+		server        models.Server
+		hops          []models.Server
+		sshArgs       []string
+		remoteCommand string
+		checkFunc     func(t *testing.T, args []string)
+	}{
+		{
+			name: "basic server with key",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				User: "admin",
+				Key:  "~/.ssh/id_rsa",
+				Port: 22,
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				hasKey := containsSequence(args, "-i", "~/.ssh/id_rsa")
+				if !hasKey {
+					t.Error("Expected -i flag with key")
+				}
+				if !contains(args, "admin@example.com") {
+					t.Error("Expected user@host in args")
+				}
+			},
+		},
+		{
+			name: "server with custom port",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				User: "admin",
+				Port: 2222,
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				hasPort := containsSequence(args, "-p", "2222")
+				if !hasPort {
+					t.Error("Expected -p flag with port 2222")
+				}
+			},
+		},
+		{
+			name: "server without user",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				Port: 22,
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if !contains(args, "example.com") {
+					t.Error("Expected host without user prefix")
+				}
+				if contains(args, "@") {
+					for _, arg := range args {
+						if arg == "example.com" {
+							return
+						}
+					}
+					t.Error("Should not have @ in host when user is empty")
+				}
+			},
+		},
+		{
+			name: "server with user override via -l flag",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				User: "admin",
+				Port: 22,
+			},
+			sshArgs:       []string{"-l", "root"},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if contains(args, "admin@example.com") {
+					t.Error("Should not prepend server user when -l flag is used")
+				}
+				if !contains(args, "root@example.com") {
+					t.Error("Expected -l user to be folded into the destination")
+				}
+				if contains(args, "-l") {
+					t.Error("Expected -l flag to be consumed")
+				}
+			},
+		},
+		{
+			name: "server with remote command",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				User: "admin",
+			},
+			sshArgs:       []string{},
+			remoteCommand: "ls -la",
+			checkFunc: func(t *testing.T, args []string) {
+				if !contains(args, "ls -la") {
+					t.Error("Expected remote command in args")
+				}
+			},
+		},
+		{
+			name: "server with ForwardAgent option",
+			server: models.Server{
+				Name:    "test",
+				Host:    "example.com",
+				User:    "admin",
+				Options: map[string]interface{}{"ForwardAgent": "yes"},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if !containsSequence(args, "-o", "ForwardAgent=yes") {
+					t.Error("Expected -o ForwardAgent=yes")
+				}
+			},
+		},
+		{
+			name: "server with ForwardAgent no",
+			server: models.Server{
+				Name:    "test",
+				Host:    "example.com",
+				User:    "admin",
+				Options: map[string]interface{}{"ForwardAgent": "no"},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if contains(args, "-A") || contains(args, "ForwardAgent=yes") {
+					t.Error("Should not enable ForwardAgent when it is no")
+				}
+				if !containsSequence(args, "-o", "ForwardAgent=no") {
+					t.Error("Expected -o ForwardAgent=no")
+				}
+			},
+		},
+		{
+			name: "server with RequestTTY force",
+			server: models.Server{
+				Name:    "test",
+				Host:    "example.com",
+				User:    "admin",
+				Options: map[string]interface{}{"RequestTTY": "force"},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if !containsSequence(args, "-o", "RequestTTY=force") {
+					t.Error("Expected -o RequestTTY=force")
+				}
+			},
+		},
+		{
+			name: "server with RequestTTY yes",
+			server: models.Server{
+				Name:    "test",
+				Host:    "example.com",
+				User:    "admin",
+				Options: map[string]interface{}{"RequestTTY": "yes"},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if !containsSequence(args, "-o", "RequestTTY=yes") {
+					t.Error("Expected -o RequestTTY=yes")
+				}
+			},
+		},
+		{
+			name: "server with LocalForward option",
+			server: models.Server{
+				Name:    "test",
+				Host:    "example.com",
+				User:    "admin",
+				Options: map[string]interface{}{"LocalForward": "8080:localhost:80"},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if !containsSequence(args, "-o", "LocalForward=8080 localhost:80") {
+					t.Errorf("Expected -o LocalForward in ssh_config form, got %v", args)
+				}
+			},
+		},
+		{
+			name: "server with every option translated in order",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				Options: map[string]interface{}{
+					"serveraliveinterval": 60,
+					"Compression":         true,
+					"LocalForward":        []interface{}{"5432 localhost:5432", "8080:localhost:80"},
+					"ProxyJump":           "bastion",
+				},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				expected := []string{
+					"-o", "Compression=yes",
+					"-o", "LocalForward=5432 localhost:5432",
+					"-o", "LocalForward=8080 localhost:80",
+					"-o", "ServerAliveInterval=60",
+					"example.com",
+				}
+				if strings.Join(args, "|") != strings.Join(expected, "|") {
+					t.Errorf("Expected %v, got %v", expected, args)
+				}
+			},
+		},
+		{
+			name: "server with additional ssh args",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				User: "admin",
+			},
+			sshArgs:       []string{"-v", "-C"},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if !contains(args, "-v") || !contains(args, "-C") {
+					t.Error("Expected additional ssh args in output")
+				}
+			},
+		},
+		{
+			name: "server with port 0",
+			server: models.Server{
+				Name: "test",
+				Host: "example.com",
+				User: "admin",
+				Port: 0,
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				if contains(args, "-p") {
+					t.Error("Should not have -p flag when port is 0")
+				}
+			},
+		},
+		{
+			name: "server with jump host",
+			server: models.Server{
+				Name: "test",
+				Host: "db.internal",
+				User: "admin",
+			},
+			hops: []models.Server{
+				{Name: "bastion", Host: "bastion.example.com", User: "ops", Port: 2222, Key: "~/.ssh/ops key"},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				expected := "ProxyCommand=ssh -i '~/.ssh/ops key' -p 2222 -W %h:%p ops@bastion.example.com"
+				if !containsSequence(args, "-o", expected) {
+					t.Errorf("Expected %q in args, got %v", expected, args)
+				}
+				if args[len(args)-1] != "admin@db.internal" {
+					t.Errorf("Expected target as last arg, got %q", args[len(args)-1])
+				}
+			},
+		},
+		{
+			name: "server with jump chain",
+			server: models.Server{
+				Name: "test",
+				Host: "db.internal",
+			},
+			hops: []models.Server{
+				{Name: "outer", Host: "outer.example.com"},
+				{Name: "inner", Host: "inner.internal", User: "ops"},
+			},
+			sshArgs:       []string{},
+			remoteCommand: "",
+			checkFunc: func(t *testing.T, args []string) {
+				expected := "ProxyCommand=ssh -o 'ProxyCommand=ssh -W %%h:%%p outer.example.com' -W %h:%p ops@inner.internal"
+				if !containsSequence(args, "-o", expected) {
+					t.Errorf("Expected %q in args, got %v", expected, args)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, err := SSH(Target{Server: tt.server, Hops: tt.hops}, tt.sshArgs, tt.remoteCommand)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if inv.Program != "ssh" {
+				t.Errorf("Expected program 'ssh', got '%s'", inv.Program)
+			}
+			tt.checkFunc(t, inv.Args)
+		})
+	}
+}
+
+func TestSSH_UnknownOption(t *testing.T) {
+	server := models.Server{
+		Name:    "test",
+		Host:    "example.com",
+		Options: map[string]interface{}{"ServerAliveInterva": 60},
+	}
+
+	_, err := SSH(Target{Server: server}, nil, "")
+	if err == nil {
+		t.Fatal("Expected error for unknown option")
+	}
+	if !strings.Contains(err.Error(), `did you mean "ServerAliveInterval"`) {
+		t.Errorf("Expected suggestion in error, got %v", err)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"user@host:22", "user@host:22"},
+		{"", "''"},
+		{"two words", "'two words'"},
+		{"it's", `'it'\''s'`},
+		{"~/.ssh/id_rsa", "'~/.ssh/id_rsa'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := Quote(tt.input); result != tt.expected {
+				t.Errorf("Quote(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSequence(slice []string, first, second string) bool {
+	for i := 0; i < len(slice)-1; i++ {
+		if slice[i] == first && slice[i+1] == second {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSFTP(t *testing.T) {
+	target := Target{
+		Server: models.Server{Name: "test", Host: "example.com", User: "admin", Port: 2222, Key: "~/.ssh/id_rsa"},
+		Hops:   []models.Server{{Name: "bastion", Host: "bastion.example.com"}},
+	}
+
+	inv, err := SFTP(target, []string{"-l", "root", "-C"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		"sftp", "-i", "~/.ssh/id_rsa", "-P", "2222",
+		"-o", "ProxyCommand=ssh -W %h:%p bastion.example.com",
+		"-C", "root@example.com",
+	}
+	if strings.Join(inv.Argv(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, inv.Argv())
+	}
+}
+
+func TestSCP(t *testing.T) {
+	server := models.Server{Name: "test", Host: "example.com", User: "admin", Port: 2222, Options: map[string]interface{}{"Compression": "yes"}}
+
+	inv, err := SCP(Target{Server: server}, []string{"-r", "-l", "deploy"}, "./dist", RemotePath(server, []string{"-l", "deploy"}, "/srv/app"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"scp", "-P", "2222", "-o", "Compression=yes", "-r", "./dist", "deploy@example.com:/srv/app"}
+	if strings.Join(inv.Argv(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, inv.Argv())
+	}
+}
+
+func TestRsync(t *testing.T) {
+	server := models.Server{Name: "test", Host: "example.com", User: "admin", Port: 2222, Key: "/keys/my key"}
+
+	inv, err := Rsync(Target{Server: server}, []string{"-az"}, "./dist/", RemotePath(server, nil, "/srv/app/"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"rsync", "-e", "ssh -i '/keys/my key' -p 2222", "-az", "./dist/", "admin@example.com:/srv/app/"}
+	if strings.Join(inv.Argv(), "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, inv.Argv())
+	}
+}
+
+func TestJumpArgs_HopOptions(t *testing.T) {
+	hops := []models.Server{{Name: "bastion", Host: "bastion.example.com", Options: map[string]interface{}{"ServerAliveInterval": 30}}}
+
+	args, err := JumpArgs(hops)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "ProxyCommand=ssh -o ServerAliveInterval=30 -W %h:%p bastion.example.com"
+	if !containsSequence(args, "-o", expected) {
+		t.Errorf("Expected %q in %v", expected, args)
+	}
+
+	hops[0].Options = map[string]interface{}{"ServerAlive": 30}
+	if _, err := JumpArgs(hops); err == nil || !strings.Contains(err.Error(), "jump host bastion") {
+		t.Errorf("Expected jump host error, got %v", err)
+	}
+}
+
+func TestJumpArgs_DropsSessionOptions(t *testing.T) {
+	hops := []models.Server{{Name: "bastion", Host: "bastion.example.com", Options: map[string]interface{}{
+		"HostKeyAlias": "bastion",
+		"LocalForward": "8080 localhost:80",
+		"RequestTTY":   "yes",
+		"ForwardAgent": "yes",
+	}}}
+
+	args, err := JumpArgs(hops)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "ProxyCommand=ssh -o HostKeyAlias=bastion -W %h:%p bastion.example.com"
+	if !containsSequence(args, "-o", expected) {
+		t.Errorf("Expected %q in %v", expected, args)
+	}
+}
+
+func TestStdioForward(t *testing.T) {
+	jump := Target{Server: models.Server{Name: "bastion", Host: "bastion.example.com", User: "ops", Port: 2222, Options: map[string]interface{}{
+		"RemoteForward": "9000 localhost:9000",
+		"Ciphers":       "aes256-gcm@openssh.com",
+	}}}
+
+	inv, err := StdioForward(jump, []string{"-o", "BatchMode=yes"}, "10.0.0.5:22")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "ssh -p 2222 -o Ciphers=aes256-gcm@openssh.com -o BatchMode=yes -W 10.0.0.5:22 ops@bastion.example.com"
+	if inv.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, inv.String())
+	}
+}
+
+func TestNewTarget(t *testing.T) {
+	inventory := models.Servers{
+		{Name: "bastion", Host: "bastion.example.com", User: "ops"},
+		{Name: "db", Host: "db.internal", Jump: models.JumpChain{"bastion"}},
+		{Name: "broken", Host: "broken.internal", Jump: models.JumpChain{"nowhere"}},
+	}
+
+	target, err := NewTarget(inventory[1], inventory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(target.Hops) != 1 || target.Hops[0].User != "ops" {
+		t.Errorf("Expected resolved bastion hop, got %+v", target.Hops)
+	}
+
+	if _, err := NewTarget(inventory[2], inventory); err == nil {
+		t.Error("Expected error for unknown jump host")
+	}
+}
+
+func TestInvocationString(t *testing.T) {
+	inv := Invocation{Program: "ssh", Args: []string{"-o", "LocalForward=8080 localhost:80", "admin@example.com", "ls -la"}}
+	expected := "ssh -o 'LocalForward=8080 localhost:80' admin@example.com 'ls -la'"
+	if inv.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, inv.String())
+	}
+}
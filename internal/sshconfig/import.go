@@ -0,0 +1,128 @@
+package sshconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// multiValued lists the keywords that may be given several times, each
+// occurrence adding a value. For all others the first value wins.
+var multiValued = map[string]bool{
+	"CertificateFile": true,
+	"DynamicForward":  true,
+	"IdentityFile":    true,
+	"LocalForward":    true,
+	"RemoteForward":   true,
+	"SendEnv":         true,
+	"SetEnv":          true,
+}
+
+// MultiValued reports whether keyword, in canonical spelling, may be given
+// several times.
+func MultiValued(keyword string) bool {
+	return multiValued[keyword]
+}
+
+// Imported is a server built from ssh_config Host blocks, with notes about
+// settings that could not be carried over.
+type Imported struct {
+	Server models.Server
+	Notes  []string
+}
+
+// ToServers turns every concrete Host pattern into a server, in the order
+// the patterns first appear. A name appearing in several blocks collects the
+// options of all of them, the first value of each keyword winning, as in
+// ssh. Blocks made only of wildcard patterns are returned as skipped.
+func ToServers(blocks []HostBlock) (imported []Imported, skipped []HostBlock) {
+	var names []string
+	options := make(map[string][]Option)
+	for _, block := range blocks {
+		concrete := block.Concrete()
+		if len(concrete) == 0 {
+			skipped = append(skipped, block)
+			continue
+		}
+		for _, name := range concrete {
+			if _, seen := options[name]; !seen {
+				names = append(names, name)
+			}
+			options[name] = append(options[name], block.Options...)
+		}
+	}
+
+	for _, name := range names {
+		imported = append(imported, toServer(name, options[name]))
+	}
+	return imported, skipped
+}
+
+func toServer(name string, options []Option) Imported {
+	result := Imported{Server: models.Server{Name: name, Host: name}}
+	s := &result.Server
+	seen := make(map[string]bool)
+	var keys []string
+	extra := make(map[string][]string)
+
+	for _, o := range options {
+		if seen[o.Key] && !multiValued[o.Key] {
+			continue
+		}
+		seen[o.Key] = true
+
+		switch o.Key {
+		case "HostName":
+			s.Host = strings.ReplaceAll(o.Value, "%h", name)
+		case "User":
+			s.User = o.Value
+		case "Port":
+			port, err := strconv.Atoi(o.Value)
+			if err != nil || port < 1 || port > 65535 {
+				result.Notes = append(result.Notes, fmt.Sprintf("invalid Port %q ignored", o.Value))
+				continue
+			}
+			s.Port = port
+		case "IdentityFile":
+			if s.Key == "" {
+				s.Key = o.Value
+				continue
+			}
+			fallthrough
+		default:
+			if _, ok := CanonicalKeyword(o.Key); !ok {
+				result.Notes = append(result.Notes, fmt.Sprintf("unknown keyword %s skipped", o.Key))
+				continue
+			}
+			if o.Key == "ProxyJump" {
+				if !strings.EqualFold(o.Value, "none") {
+					s.Jump = models.SplitJumpChain(o.Value)
+				}
+				continue
+			}
+			if _, ok := extra[o.Key]; !ok {
+				keys = append(keys, o.Key)
+			}
+			extra[o.Key] = append(extra[o.Key], o.Value)
+		}
+	}
+
+	if len(keys) > 0 {
+		s.Options = make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			values := extra[k]
+			if len(values) == 1 {
+				s.Options[k] = values[0]
+				continue
+			}
+			list := make([]interface{}, len(values))
+			for i, v := range values {
+				list[i] = v
+			}
+			s.Options[k] = list
+		}
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package sshconfig
+
+import "strings"
+
+// Keywords lists the ssh_config(5) keywords that may be set per host, in
+// their canonical spelling.
+var Keywords = []string{
+	"AddKeysToAgent",
+	"AddressFamily",
+	"BatchMode",
+	"BindAddress",
+	"BindInterface",
+	"CanonicalDomains",
+	"CanonicalizeFallbackLocal",
+	"CanonicalizeHostname",
+	"CanonicalizeMaxDots",
+	"CanonicalizePermittedCNAMEs",
+	"CASignatureAlgorithms",
+	"CertificateFile",
+	"ChallengeResponseAuthentication",
+	"ChannelTimeout",
+	"CheckHostIP",
+	"Ciphers",
+	"ClearAllForwardings",
+	"Compression",
+	"ConnectionAttempts",
+	"ConnectTimeout",
+	"ControlMaster",
+	"ControlPath",
+	"ControlPersist",
+	"DynamicForward",
+	"EnableEscapeCommandline",
+	"EnableSSHKeysign",
+	"EscapeChar",
+	"ExitOnForwardFailure",
+	"FingerprintHash",
+	"ForkAfterAuthentication",
+	"ForwardAgent",
+	"ForwardX11",
+	"ForwardX11Timeout",
+	"ForwardX11Trusted",
+	"GatewayPorts",
+	"GlobalKnownHostsFile",
+	"GSSAPIAuthentication",
+	"GSSAPIDelegateCredentials",
+	"HashKnownHosts",
+	"HostbasedAcceptedAlgorithms",
+	"HostbasedAuthentication",
+	"HostbasedKeyTypes",
+	"HostKeyAlgorithms",
+	"HostKeyAlias",
+	"HostName",
+	"IdentitiesOnly",
+	"IdentityAgent",
+	"IdentityFile",
+	"IgnoreUnknown",
+	"IPQoS",
+	"KbdInteractiveAuthentication",
+	"KbdInteractiveDevices",
+	"KexAlgorithms",
+	"KnownHostsCommand",
+	"LocalCommand",
+	"LocalForward",
+	"LogLevel",
+	"LogVerbose",
+	"MACs",
+	"NoHostAuthenticationForLocalhost",
+	"NumberOfPasswordPrompts",
+	"ObscureKeystrokeTiming",
+	"PasswordAuthentication",
+	"PermitLocalCommand",
+	"PermitRemoteOpen",
+	"PKCS11Provider",
+	"Port",
+	"PreferredAuthentications",
+	"ProxyCommand",
+	"ProxyJump",
+	"ProxyUseFdpass",
+	"PubkeyAcceptedAlgorithms",
+	"PubkeyAcceptedKeyTypes",
+	"PubkeyAuthentication",
+	"RekeyLimit",
+	"RemoteCommand",
+	"RemoteForward",
+	"RequestTTY",
+	"RequiredRSASize",
+	"RevokedHostKeys",
+	"SecurityKeyProvider",
+	"SendEnv",
+	"ServerAliveCountMax",
+	"ServerAliveInterval",
+	"SessionType",
+	"SetEnv",
+	"StdinNull",
+	"StreamLocalBindMask",
+	"StreamLocalBindUnlink",
+	"StrictHostKeyChecking",
+	"SyslogFacility",
+	"Tag",
+	"TCPKeepAlive",
+	"Tunnel",
+	"TunnelDevice",
+	"UpdateHostKeys",
+	"UseKeychain",
+	"User",
+	"UserKnownHostsFile",
+	"VerifyHostKeyDNS",
+	"VisualHostKey",
+	"XAuthLocation",
+}
+
+var keywordIndex = func() map[string]string {
+	index := make(map[string]string, len(Keywords))
+	for _, k := range Keywords {
+		index[strings.ToLower(k)] = k
+	}
+	return index
+}()
+
+// CanonicalKeyword returns the canonical spelling of an ssh_config keyword.
+// Keywords are matched case-insensitively, like ssh does.
+func CanonicalKeyword(name string) (string, bool) {
+	k, ok := keywordIndex[strings.ToLower(name)]
+	return k, ok
+}
+
+// SuggestKeyword returns the known keyword closest to name, or "" if none
+// is close enough to be a plausible typo.
+func SuggestKeyword(name string) string {
+	lower := strings.ToLower(name)
+	best, bestDistance := "", len(lower)/3+1
+	for _, k := range Keywords {
+		if d := levenshtein(lower, strings.ToLower(k)); d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,181 @@
+package sshconfig
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Option is a single ssh_config keyword/value pair.
+type Option struct {
+	Key   string
+	Value string
+}
+
+// ParseOptions validates a server's free-form options and flattens them into
+// keyword/value pairs sorted by keyword. List values expand into one pair per
+// element, keeping their order. ProxyJump is left out because sshy resolves
+// jump chains itself.
+func ParseOptions(options map[string]interface{}) ([]Option, error) {
+	keys := make([]string, 0, len(options))
+	canonical := make(map[string]string, len(options))
+	for key := range options {
+		k, ok := CanonicalKeyword(key)
+		if !ok {
+			if suggestion := SuggestKeyword(key); suggestion != "" {
+				return nil, fmt.Errorf("unknown ssh option %q (did you mean %q?)", key, suggestion)
+			}
+			return nil, fmt.Errorf("unknown ssh option %q", key)
+		}
+		if other, dup := canonical[k]; dup {
+			return nil, fmt.Errorf("ssh option %q is set twice (%q and %q)", k, other, key)
+		}
+		canonical[k] = key
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var result []Option
+	for _, k := range keys {
+		if k == "ProxyJump" {
+			continue
+		}
+		values, err := optionValues(k, options[canonical[k]])
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			result = append(result, Option{Key: k, Value: v})
+		}
+	}
+	return result, nil
+}
+
+// sessionKeywords configure the session or its forwardings rather than the
+// connection. A jump host only carries a -W stdio forward, where they would
+// bind ports, ask for a TTY or run commands, so HopOptionArgs leaves them out.
+var sessionKeywords = map[string]bool{
+	"DynamicForward":          true,
+	"ExitOnForwardFailure":    true,
+	"ForkAfterAuthentication": true,
+	"ForwardAgent":            true,
+	"ForwardX11":              true,
+	"ForwardX11Timeout":       true,
+	"ForwardX11Trusted":       true,
+	"GatewayPorts":            true,
+	"LocalCommand":            true,
+	"LocalForward":            true,
+	"PermitLocalCommand":      true,
+	"PermitRemoteOpen":        true,
+	"RemoteCommand":           true,
+	"RemoteForward":           true,
+	"RequestTTY":              true,
+	"SendEnv":                 true,
+	"SessionType":             true,
+	"SetEnv":                  true,
+	"StdinNull":               true,
+	"Tunnel":                  true,
+	"TunnelDevice":            true,
+}
+
+// OptionArgs renders options as repeated -o Key=Value arguments, which ssh,
+// scp and sftp all accept.
+func OptionArgs(options map[string]interface{}) ([]string, error) {
+	return optionArgs(options, false)
+}
+
+// HopOptionArgs is OptionArgs for a jump host: only the options that affect
+// the connection itself, such as authentication, ciphers or HostKeyAlias,
+// are kept.
+func HopOptionArgs(options map[string]interface{}) ([]string, error) {
+	return optionArgs(options, true)
+}
+
+func optionArgs(options map[string]interface{}, hop bool) ([]string, error) {
+	parsed, err := ParseOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, 2*len(parsed))
+	for _, o := range parsed {
+		if hop && sessionKeywords[o.Key] {
+			continue
+		}
+		args = append(args, "-o", o.Key+"="+o.Value)
+	}
+	return args, nil
+}
+
+func optionValues(key string, value interface{}) ([]string, error) {
+	if list, ok := value.([]interface{}); ok {
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			v, err := optionValue(key, item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	}
+	if list, ok := value.([]string); ok {
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			v, err := optionValue(key, item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	}
+	v, err := optionValue(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return []string{v}, nil
+}
+
+func optionValue(key string, value interface{}) (string, error) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case bool:
+		s = "no"
+		if v {
+			s = "yes"
+		}
+	case int:
+		s = strconv.Itoa(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return "", fmt.Errorf("ssh option %s has unsupported value %v", key, value)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("ssh option %s has an empty value", key)
+	}
+	if key == "LocalForward" || key == "RemoteForward" {
+		s = normalizeForward(s)
+	}
+	return s, nil
+}
+
+// normalizeForward turns the -L style "port:host:hostport" into the
+// "port host:hostport" form that ssh_config requires.
+func normalizeForward(value string) string {
+	if strings.ContainsAny(value, " \t") || strings.Contains(value, "[") {
+		return value
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) < 3 {
+		return value
+	}
+	split := len(parts) - 2
+	return strings.Join(parts[:split], ":") + " " + strings.Join(parts[split:], ":")
+}
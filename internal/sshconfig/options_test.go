@@ -0,0 +1,153 @@
+package sshconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalKeyword(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"ServerAliveInterval", "ServerAliveInterval", true},
+		{"serveraliveinterval", "ServerAliveInterval", true},
+		{"HOSTNAME", "HostName", true},
+		{"Host", "", false},
+		{"NotAKeyword", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			k, ok := CanonicalKeyword(tt.input)
+			if k != tt.expected || ok != tt.ok {
+				t.Errorf("CanonicalKeyword(%q) = %q, %v, expected %q, %v", tt.input, k, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSuggestKeyword(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ServerAlivInterval", "ServerAliveInterval"},
+		{"FowardAgent", "ForwardAgent"},
+		{"IdentityFiles", "IdentityFile"},
+		{"Banana", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := SuggestKeyword(tt.input); got != tt.expected {
+				t.Errorf("SuggestKeyword(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOptionArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  map[string]interface{}
+		expected []string
+		wantErr  string
+	}{
+		{
+			name:     "nil options",
+			options:  nil,
+			expected: []string{},
+		},
+		{
+			name: "sorted and canonicalised",
+			options: map[string]interface{}{
+				"user":                "deploy",
+				"ServerAliveInterval": 60,
+				"ForwardAgent":        "yes",
+			},
+			expected: []string{"-o", "ForwardAgent=yes", "-o", "ServerAliveInterval=60", "-o", "User=deploy"},
+		},
+		{
+			name:     "booleans and floats",
+			options:  map[string]interface{}{"Compression": false, "ConnectTimeout": float64(10)},
+			expected: []string{"-o", "Compression=no", "-o", "ConnectTimeout=10"},
+		},
+		{
+			name: "list values repeat",
+			options: map[string]interface{}{
+				"LocalForward": []interface{}{"8080:localhost:80", "127.0.0.1:5432:db:5432"},
+				"SendEnv":      []string{"LANG", "LC_*"},
+			},
+			expected: []string{
+				"-o", "LocalForward=8080 localhost:80",
+				"-o", "LocalForward=127.0.0.1:5432 db:5432",
+				"-o", "SendEnv=LANG",
+				"-o", "SendEnv=LC_*",
+			},
+		},
+		{
+			name:     "ProxyJump is skipped",
+			options:  map[string]interface{}{"ProxyJump": "bastion"},
+			expected: []string{},
+		},
+		{
+			name:    "typo",
+			options: map[string]interface{}{"StrictHostKeyCheking": "no"},
+			wantErr: `did you mean "StrictHostKeyChecking"?`,
+		},
+		{
+			name:    "unknown without suggestion",
+			options: map[string]interface{}{"Flux": "capacitor"},
+			wantErr: `unknown ssh option "Flux"`,
+		},
+		{
+			name:    "duplicate spelling",
+			options: map[string]interface{}{"User": "a", "user": "b"},
+			wantErr: "set twice",
+		},
+		{
+			name:    "nested map",
+			options: map[string]interface{}{"SetEnv": map[string]interface{}{"A": "b"}},
+			wantErr: "unsupported value",
+		},
+		{
+			name:    "empty value",
+			options: map[string]interface{}{"User": " "},
+			wantErr: "empty value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := OptionArgs(tt.options)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(args, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("OptionArgs() = %v, expected %v", args, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOptionArgs_Deterministic(t *testing.T) {
+	options := map[string]interface{}{
+		"ServerAliveInterval": 30, "ServerAliveCountMax": 3, "Compression": "yes",
+		"ForwardAgent": "no", "LogLevel": "ERROR", "TCPKeepAlive": "yes",
+	}
+	first, _ := OptionArgs(options)
+	for i := 0; i < 20; i++ {
+		args, _ := OptionArgs(options)
+		if strings.Join(args, "|") != strings.Join(first, "|") {
+			t.Fatalf("Expected stable argv, got %v and %v", first, args)
+		}
+	}
+}
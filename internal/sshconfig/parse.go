@@ -0,0 +1,182 @@
+package sshconfig
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxIncludeDepth guards against Include loops, like ssh's own limit.
+const maxIncludeDepth = 16
+
+// HostBlock is a Host section of an ssh_config file with its options in file
+// order. Keywords are in their canonical spelling; unknown keywords are kept
+// as written.
+type HostBlock struct {
+	Patterns []string
+	Options  []Option
+	File     string
+	Line     int
+}
+
+// Concrete returns the patterns that name a single host, leaving out
+// wildcards and negations.
+func (b HostBlock) Concrete() []string {
+	var names []string
+	for _, p := range b.Patterns {
+		if !strings.ContainsAny(p, "*?!") {
+			names = append(names, p)
+		}
+	}
+	return names
+}
+
+type parser struct {
+	home   string
+	blocks []HostBlock
+	// current is the index of the Host block being read, or -1 outside one
+	// (before the first Host, or inside a Match block).
+	current int
+}
+
+// ParseFile reads an ssh_config file and the files it includes. Relative
+// Include paths are resolved against ~/.ssh, as ssh does for user configs.
+func ParseFile(path string) ([]HostBlock, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{home: home, current: -1}
+	if err := p.parseFile(expandHome(path, home), 0); err != nil {
+		return nil, err
+	}
+	return p.blocks, nil
+}
+
+func (p *parser) parseFile(path string, depth int) error {
+	if depth > maxIncludeDepth {
+		return fmt.Errorf("%s: too many nested includes", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		keyword, args, err := splitLine(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		if keyword == "" {
+			continue
+		}
+
+		switch strings.ToLower(keyword) {
+		case "host":
+			if len(args) == 0 {
+				return fmt.Errorf("%s:%d: Host without patterns", path, lineNo)
+			}
+			p.blocks = append(p.blocks, HostBlock{Patterns: args, File: path, Line: lineNo})
+			p.current = len(p.blocks) - 1
+		case "match":
+			p.current = -1
+		case "include":
+			for _, pattern := range args {
+				if err := p.include(pattern, depth); err != nil {
+					return fmt.Errorf("%s:%d: %w", path, lineNo, err)
+				}
+			}
+		default:
+			if p.current < 0 {
+				continue
+			}
+			if k, ok := CanonicalKeyword(keyword); ok {
+				keyword = k
+			}
+			block := &p.blocks[p.current]
+			block.Options = append(block.Options, Option{Key: keyword, Value: strings.Join(args, " ")})
+		}
+	}
+	return scanner.Err()
+}
+
+// include parses the files matching pattern in order. Like ssh, a pattern
+// matching no files is not an error.
+func (p *parser) include(pattern string, depth int) error {
+	pattern = expandHome(pattern, p.home)
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(p.home, ".ssh", pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if info, err := os.Stat(match); err != nil || info.IsDir() {
+			continue
+		}
+		if err := p.parseFile(match, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitLine splits a config line into its keyword and arguments. The keyword
+// may be separated by whitespace or "=", and arguments may be double-quoted.
+func splitLine(line string) (string, []string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil, nil
+	}
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return line, nil, nil
+	}
+	keyword := line[:end]
+	rest := strings.TrimLeft(line[end:], " \t")
+	rest = strings.TrimLeft(strings.TrimPrefix(rest, "="), " \t")
+
+	var args []string
+	var current strings.Builder
+	inQuotes, hasArg := false, false
+	for _, r := range rest {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if inQuotes {
+		return "", nil, fmt.Errorf("unterminated quote")
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return keyword, args, nil
+}
+
+func expandHome(path, home string) string {
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
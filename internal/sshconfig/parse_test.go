@@ -0,0 +1,163 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeFile(t, filepath.Join(home, ".ssh", "config"), `# Global defaults
+ServerAliveInterval 60
+
+Include conf.d/*.conf
+
+Host web-1 web-2
+    HostName %h.example.com
+    User deploy
+    identityfile ~/.ssh/deploy
+
+Match host *.internal
+    User admin
+
+Host bastion
+    HostName=bastion.example.com
+    Port = 2222
+    RemoteCommand "tmux new -A -s main"
+
+Host *
+    AddKeysToAgent yes
+`)
+	writeFile(t, filepath.Join(home, ".ssh", "conf.d", "10-db.conf"), "Host db-1\n  HostName 10.0.1.1\n  ProxyJump bastion\n")
+	writeFile(t, filepath.Join(home, ".ssh", "conf.d", "20-cache.conf"), "Host cache-*\n  User redis\n")
+
+	blocks, err := ParseFile("~/.ssh/config")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var patterns []string
+	for _, b := range blocks {
+		patterns = append(patterns, strings.Join(b.Patterns, " "))
+	}
+	expected := []string{"db-1", "cache-*", "web-1 web-2", "bastion", "*"}
+	if strings.Join(patterns, "|") != strings.Join(expected, "|") {
+		t.Fatalf("Expected blocks %v, got %v", expected, patterns)
+	}
+
+	web := blocks[2]
+	if len(web.Options) != 3 || web.Options[2].Key != "IdentityFile" || web.Options[2].Value != "~/.ssh/deploy" {
+		t.Errorf("Expected canonical keywords and Match options left out, got %+v", web.Options)
+	}
+	bastion := blocks[3]
+	if bastion.Options[0] != (Option{Key: "HostName", Value: "bastion.example.com"}) || bastion.Options[1] != (Option{Key: "Port", Value: "2222"}) {
+		t.Errorf("Expected = separators to be accepted, got %+v", bastion.Options)
+	}
+	if bastion.Options[2].Value != "tmux new -A -s main" {
+		t.Errorf("Expected quoted argument to be unquoted, got %q", bastion.Options[2].Value)
+	}
+	if blocks[0].File != filepath.Join(home, ".ssh", "conf.d", "10-db.conf") || blocks[0].Line != 1 {
+		t.Errorf("Expected block position in the included file, got %s:%d", blocks[0].File, blocks[0].Line)
+	}
+}
+
+func TestParseFile_Errors(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	loop := filepath.Join(home, "loop.conf")
+	writeFile(t, loop, "Include "+loop+"\n")
+	quote := filepath.Join(home, "quote.conf")
+	writeFile(t, quote, "Host a\n  RemoteCommand \"unterminated\n")
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"missing file", filepath.Join(home, "missing"), "no such file"},
+		{"include loop", loop, "too many nested includes"},
+		{"unterminated quote", quote, "quote.conf:2: unterminated quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseFile(tt.path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToServers(t *testing.T) {
+	blocks := []HostBlock{
+		{Patterns: []string{"web-1", "web-2"}, Options: []Option{
+			{Key: "HostName", Value: "%h.example.com"},
+			{Key: "User", Value: "deploy"},
+			{Key: "IdentityFile", Value: "~/.ssh/deploy"},
+			{Key: "IdentityFile", Value: "~/.ssh/fallback"},
+			{Key: "LocalForward", Value: "8080 localhost:80"},
+		}},
+		{Patterns: []string{"*.example.com", "!bad"}, Options: []Option{{Key: "User", Value: "ignored"}}},
+		{Patterns: []string{"db-1"}, Options: []Option{
+			{Key: "HostName", Value: "10.0.1.1"},
+			{Key: "Port", Value: "5022"},
+			{Key: "ProxyJump", Value: "bastion,admin@gw.example.com:2222"},
+			{Key: "Compression", Value: "yes"},
+			{Key: "Frobnicate", Value: "yes"},
+		}},
+		{Patterns: []string{"web-1"}, Options: []Option{
+			{Key: "User", Value: "root"},
+			{Key: "Port", Value: "2200"},
+		}},
+		{Patterns: []string{"broken"}, Options: []Option{{Key: "Port", Value: "ssh"}}},
+	}
+
+	imported, skipped := ToServers(blocks)
+	if len(skipped) != 1 || skipped[0].Patterns[0] != "*.example.com" {
+		t.Errorf("Expected the wildcard block to be skipped, got %+v", skipped)
+	}
+	if len(imported) != 4 {
+		t.Fatalf("Expected 4 servers, got %d", len(imported))
+	}
+
+	web1 := imported[0].Server
+	if web1.Name != "web-1" || web1.Host != "web-1.example.com" || web1.User != "deploy" || web1.Port != 2200 || web1.Key != "~/.ssh/deploy" {
+		t.Errorf("Unexpected web-1: %+v", web1)
+	}
+	if web1.Options["IdentityFile"] != "~/.ssh/fallback" || web1.Options["LocalForward"] != "8080 localhost:80" {
+		t.Errorf("Unexpected web-1 options: %v", web1.Options)
+	}
+	if imported[1].Server.Host != "web-2.example.com" || imported[1].Server.Port != 0 {
+		t.Errorf("Unexpected web-2: %+v", imported[1].Server)
+	}
+
+	db := imported[2]
+	if db.Server.Port != 5022 || strings.Join(db.Server.Jump, ",") != "bastion,admin@gw.example.com:2222" || db.Server.Options["Compression"] != "yes" {
+		t.Errorf("Unexpected db-1: %+v", db.Server)
+	}
+	if _, ok := db.Server.Options["ProxyJump"]; ok {
+		t.Error("Expected ProxyJump to become the jump chain, not an option")
+	}
+	if len(db.Notes) != 1 || !strings.Contains(db.Notes[0], "Frobnicate") {
+		t.Errorf("Expected a note about the unknown keyword, got %v", db.Notes)
+	}
+
+	if len(imported[3].Notes) != 1 || !strings.Contains(imported[3].Notes[0], "invalid Port") {
+		t.Errorf("Expected a note about the invalid port, got %v", imported[3].Notes)
+	}
+}
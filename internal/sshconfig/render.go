@@ -0,0 +1,110 @@
+package sshconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+// Markers delimit the block that sshy manages inside a user's ssh_config.
+const (
+	BeginMarker = "# BEGIN sshy managed block"
+	EndMarker   = "# END sshy managed block"
+)
+
+// Render writes servers as ssh_config Host blocks, one per server in
+// inventory order, named after the server. Jump hosts are rendered as
+// ProxyJump by name, so a hop that is itself in the inventory is reached
+// with its own Host block. Servers whose name cannot be used as a Host
+// pattern, or that repeat an earlier name, are left out with a comment.
+func Render(servers models.Servers) (string, error) {
+	var b strings.Builder
+	seen := make(map[string]bool, len(servers))
+	for _, s := range servers {
+		if reason := unusableName(s.Name, seen); reason != "" {
+			fmt.Fprintf(&b, "# Skipped %q: %s\n\n", s.Name, reason)
+			continue
+		}
+		seen[s.Name] = true
+
+		options, err := ParseOptions(s.Options)
+		if err != nil {
+			return "", fmt.Errorf("server %s: %w", s.Name, err)
+		}
+
+		fmt.Fprintf(&b, "Host %s\n", s.Name)
+		fmt.Fprintf(&b, "  HostName %s\n", quoteArg(s.Host))
+		if s.User != "" {
+			fmt.Fprintf(&b, "  User %s\n", quoteArg(s.User))
+		}
+		if s.Port != 0 && s.Port != 22 {
+			fmt.Fprintf(&b, "  Port %d\n", s.Port)
+		}
+		if s.Key != "" {
+			fmt.Fprintf(&b, "  IdentityFile %s\n", quoteArg(s.Key))
+		}
+		if hops := s.JumpHops(); len(hops) > 0 {
+			fmt.Fprintf(&b, "  ProxyJump %s\n", strings.Join(hops, ","))
+		}
+		for _, o := range options {
+			fmt.Fprintf(&b, "  %s %s\n", o.Key, o.Value)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// ManagedBlock wraps rendered Host blocks in the markers. It ends with
+// "Match all" so that whatever follows the block in the file is not taken
+// as part of its last Host section.
+func ManagedBlock(rendered string) string {
+	return BeginMarker + "\n" +
+		"# Generated by sshy. Changes here are overwritten; run \"sshy export ssh-config\" to update.\n\n" +
+		rendered +
+		"Match all\n" +
+		EndMarker + "\n"
+}
+
+// ReplaceBlock puts block in place of the managed block in content, leaving
+// the rest untouched. Without an existing block, block is put at the top,
+// where its Host sections take precedence over the user's catch-all ones.
+func ReplaceBlock(content, block string) (string, error) {
+	begin := strings.Index(content, BeginMarker+"\n")
+	end := strings.Index(content, EndMarker)
+	switch {
+	case begin < 0 && end < 0:
+		if content == "" {
+			return block, nil
+		}
+		return block + "\n" + content, nil
+	case begin < 0 || end < begin:
+		return "", fmt.Errorf("unbalanced sshy managed block markers")
+	}
+
+	end += len(EndMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:begin] + block + content[end:], nil
+}
+
+func unusableName(name string, seen map[string]bool) string {
+	switch {
+	case name == "":
+		return "empty name"
+	case strings.ContainsAny(name, " \t\"*?!,"):
+		return "name is not a valid Host pattern"
+	case seen[name]:
+		return "duplicate name"
+	}
+	return ""
+}
+
+// quoteArg quotes a single-argument value that contains whitespace.
+func quoteArg(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
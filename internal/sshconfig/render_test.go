@@ -0,0 +1,97 @@
+package sshconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/omisai-tech/sshy/internal/models"
+)
+
+func TestRender(t *testing.T) {
+	servers := models.Servers{
+		{Name: "bastion", Host: "bastion.example.com", User: "ops", Port: 2222, Key: "~/.ssh/ops key"},
+		{Name: "web-1", Host: "10.0.0.1", User: "deploy", Jump: models.JumpChain{"bastion"}, Options: map[string]interface{}{
+			"ForwardAgent": true,
+			"LocalForward": "8080:localhost:80",
+		}},
+		{Name: "web *", Host: "10.0.0.2"},
+		{Name: "web-1", Host: "10.0.0.3"},
+	}
+
+	rendered, err := Render(servers)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `Host bastion
+  HostName bastion.example.com
+  User ops
+  Port 2222
+  IdentityFile "~/.ssh/ops key"
+
+Host web-1
+  HostName 10.0.0.1
+  User deploy
+  ProxyJump bastion
+  ForwardAgent yes
+  LocalForward 8080 localhost:80
+
+# Skipped "web *": name is not a valid Host pattern
+
+# Skipped "web-1": duplicate name
+
+`
+	if rendered != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, rendered)
+	}
+}
+
+func TestRender_InvalidOption(t *testing.T) {
+	_, err := Render(models.Servers{{Name: "web", Host: "web", Options: map[string]interface{}{"ForwardAgnet": "yes"}}})
+	if err == nil || !strings.Contains(err.Error(), "server web") {
+		t.Errorf("Expected an error naming the server, got %v", err)
+	}
+}
+
+func TestReplaceBlock(t *testing.T) {
+	block := ManagedBlock("Host web\n  HostName web.example.com\n\n")
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "empty file",
+			content:  "",
+			expected: block,
+		},
+		{
+			name:     "prepended to existing entries",
+			content:  "Host *\n  User me\n",
+			expected: block + "\nHost *\n  User me\n",
+		},
+		{
+			name:     "replaced in place",
+			content:  "Host a\n  User a\n\n" + BeginMarker + "\nHost old\n" + EndMarker + "\n\nHost b\n  User b\n",
+			expected: "Host a\n  User a\n\n" + block + "\nHost b\n  User b\n",
+		},
+		{
+			name:    "missing end marker",
+			content: BeginMarker + "\nHost old\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ReplaceBlock(tt.content, block)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}